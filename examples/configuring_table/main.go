@@ -2,6 +2,7 @@ package main
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/antlko/go-gpa/db"
 	"github.com/antlko/go-gpa/gpa"
@@ -89,9 +90,15 @@ func main() {
 		panic(err)
 	}
 
+	// Save data to DB and get generated ID back
+	ann := User{Name: "Ann"}
+	if err := gpa.From[User]().InsertReturning(&ann); err != nil {
+		panic(err)
+	}
+
 	// Update entity
 	if err := gpa.From[User]().Update(User{
-		ID:   1,
+		ID:   ann.ID,
 		Name: "Doe",
 	}); err != nil {
 		panic(err)
@@ -106,7 +113,7 @@ func main() {
 
 	// Find Data from DB by ID
 	user, err := gpa.From[User]().FindByID(1)
-	if err != nil && err != sql.ErrNoRows {
+	if err != nil && !errors.Is(err, gpa.ErrNotFound) {
 		panic(err)
 	}
 
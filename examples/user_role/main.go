@@ -40,7 +40,7 @@ type Role struct {
 
 // UserRole GPA entity
 type UserRole struct {
-	Role interface{} `db:"role_id" join:"roles" mappedBy:"id" fetch:"lazy"`
+	Role interface{} `db:"role_id" join:"roles" mappedBy:"id"`
 	User interface{} `db:"user_id" join:"users" mappedBy:"id"`
 }
 
@@ -66,11 +66,13 @@ func main() {
 	DB := db.NewPGInstance(db.PGConfig{Host: host, Port: port, User: user, Password: password, DBName: dbname})
 
 	// Global initialization go-gpa Engine
-	gpa.NewEngine(DB, gpa.Config{IsLazy: true})
-	var err error
+	engine := gpa.NewEngine(DB, gpa.Config{IsLazy: true})
 
-	gpa.From[UserRole]()
-	gpa.From[Role]()
+	// Lazy entities should be registered before fetching
+	err := gpa.RegisterAll(engine, UserRole{}, Role{}, User{})
+	if err != nil {
+		panic(err)
+	}
 
 	err = gpa.From[User]().Inserts([]User{{Name: "Ann"}, {Name: "San"}, {Name: "Vi"}})
 	if err != nil {
@@ -7,7 +7,7 @@ require (
 	github.com/jackc/pgconn v1.13.0
 	github.com/jackc/pgx/v4 v4.17.2
 	github.com/jmoiron/sqlx v1.3.5
-	github.com/pkg/errors v0.8.1
+	github.com/pkg/errors v0.9.1
 )
 
 require (
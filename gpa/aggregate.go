@@ -0,0 +1,223 @@
+package gpa
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+type AggregateFunc string
+
+const (
+	AggCount AggregateFunc = "COUNT"
+	AggSum   AggregateFunc = "SUM"
+	AggAvg   AggregateFunc = "AVG"
+	AggMin   AggregateFunc = "MIN"
+	AggMax   AggregateFunc = "MAX"
+)
+
+// Aggregate aggregate function of GroupBy, As is result column name matched with db tag of the result struct.
+// Column could be empty for COUNT(*), As defaults to func_column, f.e. sum_views.
+type Aggregate struct {
+	Func   AggregateFunc
+	Column string
+	As     string
+}
+
+// Count returns number of rows matched by filters
+func (e *Entity[entityType]) Count(filters []F) (int64, error) {
+	return e.CountContext(context.Background(), filters)
+}
+
+func (e *Entity[entityType]) CountContext(ctx context.Context, filters []F) (int64, error) {
+	var count int64
+	if err := e.selectAggregate(ctx, &count, "COUNT(*)", filters); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exists checks if any row is matched by filters
+func (e *Entity[entityType]) Exists(filters []F) (bool, error) {
+	return e.ExistsContext(context.Background(), filters)
+}
+
+func (e *Entity[entityType]) ExistsContext(ctx context.Context, filters []F) (bool, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return false, err
+	}
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM " + tableName + whereElements + ")"
+	if err := e.engine.GetInstance().GetContext(ctx, &exists, query, values...); err != nil {
+		return false, classifyError(err)
+	}
+	return exists, nil
+}
+
+// Sum returns sum of the column of rows matched by filters, 0 when there are no rows.
+//
+//	views, err := gpa.Sum[int64](gpa.From[Document](), "views", nil)
+func Sum[resultType any, entityType any](e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return SumContext[resultType](context.Background(), e, column, filters)
+}
+
+func SumContext[resultType any, entityType any](ctx context.Context, e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return aggregate[resultType](ctx, e, AggSum, column, filters)
+}
+
+// Avg returns average of the column, resultType should be nullable (pointer or sql.Null*) when there could be no rows
+func Avg[resultType any, entityType any](e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return AvgContext[resultType](context.Background(), e, column, filters)
+}
+
+func AvgContext[resultType any, entityType any](ctx context.Context, e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return aggregate[resultType](ctx, e, AggAvg, column, filters)
+}
+
+// Min returns minimal value of the column, resultType should be nullable (pointer or sql.Null*) when there could be no rows
+func Min[resultType any, entityType any](e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return MinContext[resultType](context.Background(), e, column, filters)
+}
+
+func MinContext[resultType any, entityType any](ctx context.Context, e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return aggregate[resultType](ctx, e, AggMin, column, filters)
+}
+
+// Max returns maximal value of the column, resultType should be nullable (pointer or sql.Null*) when there could be no rows
+func Max[resultType any, entityType any](e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return MaxContext[resultType](context.Background(), e, column, filters)
+}
+
+func MaxContext[resultType any, entityType any](ctx context.Context, e *Entity[entityType], column string, filters []F) (resultType, error) {
+	return aggregate[resultType](ctx, e, AggMax, column, filters)
+}
+
+func aggregate[resultType any, entityType any](ctx context.Context, e *Entity[entityType], fn AggregateFunc, column string, filters []F) (resultType, error) {
+	result := *new(resultType)
+
+	w, err := e.newWhereBuilder(1)
+	if err != nil {
+		return result, err
+	}
+	quoted, err := w.column(column)
+	if err != nil {
+		return result, err
+	}
+
+	expr := fmt.Sprintf("%s(%s)", fn, quoted)
+	if fn == AggSum {
+		expr = "COALESCE(" + expr + ", 0)"
+	}
+	if err := e.selectAggregate(ctx, &result, expr, filters); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// GroupBy groups rows matched by filters by columns and scans groups with aggregates into dest,
+// dest should be pointer to slice of structs with db tags of group columns and aggregates names.
+func (e *Entity[entityType]) GroupBy(dest interface{}, columns []string, aggregates []Aggregate, filters []F) error {
+	return e.GroupByContext(context.Background(), dest, columns, aggregates, filters)
+}
+
+func (e *Entity[entityType]) GroupByContext(ctx context.Context, dest interface{}, columns []string, aggregates []Aggregate, filters []F) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
+	}
+	w, err := e.newWhereBuilder(1)
+	if err != nil {
+		return err
+	}
+
+	groupColumns := make([]string, 0)
+	for _, c := range columns {
+		quoted, err := w.column(c)
+		if err != nil {
+			return err
+		}
+		groupColumns = append(groupColumns, quoted)
+	}
+
+	selects := append(make([]string, 0), groupColumns...)
+	for _, a := range aggregates {
+		expr, err := a.expr(w)
+		if err != nil {
+			return err
+		}
+		selects = append(selects, expr)
+	}
+	if len(selects) == 0 {
+		return errors.New("gpa group by has no columns to select")
+	}
+
+	query := "SELECT " + strings.Join(selects, ", ") + " FROM " + tableName
+	if len(filters) > 0 {
+		conditions, _, err := w.conditions(filters)
+		if err != nil {
+			return err
+		}
+		query += " WHERE " + conditions
+	}
+	if len(groupColumns) > 0 {
+		query += " GROUP BY " + strings.Join(groupColumns, ", ")
+	}
+
+	if err := e.engine.GetInstance().SelectContext(ctx, dest, query, w.values...); err != nil {
+		return classifyError(err)
+	}
+	return nil
+}
+
+// expr builds aggregate select expression with alias
+func (a Aggregate) expr(w *whereBuilder) (string, error) {
+	switch a.Func {
+	case AggCount, AggSum, AggAvg, AggMin, AggMax:
+	default:
+		return "", errors.Errorf("gpa unknown aggregate function %q", a.Func)
+	}
+
+	column := "*"
+	if a.Column != "" {
+		quoted, err := w.column(a.Column)
+		if err != nil {
+			return "", err
+		}
+		column = quoted
+	} else if a.Func != AggCount {
+		return "", errors.Errorf("gpa aggregate %s requires column", a.Func)
+	}
+
+	alias := a.As
+	if alias == "" {
+		alias = strings.ToLower(string(a.Func))
+		if a.Column != "" {
+			alias += "_" + a.Column
+		}
+	}
+	return fmt.Sprintf("%s(%s) AS %s", a.Func, column, quoteIdent(alias)), nil
+}
+
+// selectAggregate selects single aggregate expression of rows matched by filters into dest
+func (e *Entity[entityType]) selectAggregate(ctx context.Context, dest interface{}, expr string, filters []F) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
+	}
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := e.engine.GetInstance().GetContext(ctx, dest, "SELECT "+expr+" FROM "+tableName+whereElements, values...); err != nil {
+		return classifyError(err)
+	}
+	return nil
+}
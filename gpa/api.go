@@ -1,211 +1,374 @@
 package gpa
 
 import (
+	"context"
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
-	"log"
 	"reflect"
 	"strings"
 )
 
 type Entity[entityType any] struct {
 	entityObj any
+	engine    *Engine
+	// err entity initialization error, returned by every method
+	err error
+}
+
+// tableName returns quoted entity table name
+func (e *Entity[entityType]) tableName() (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+
+	tableName, ok := e.engine.GetTableName(e.entityObj)
+	if !ok {
+		return "", errors.Wrapf(ErrNotConfigured, "entity %s", reflect.TypeOf(e.entityObj))
+	}
+	return quoteTable(tableName), nil
 }
 
 func (e *Entity[entityType]) Get(where string, args ...interface{}) (entityType, error) {
+	return e.GetContext(context.Background(), where, args...)
+}
+
+func (e *Entity[entityType]) GetContext(ctx context.Context, where string, args ...interface{}) (entityType, error) {
 	entity := e.entityObj.(entityType)
 
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return entity, errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+	tableName, err := e.tableName()
+	if err != nil {
+		return entity, err
 	}
 
 	if where != "" {
 		where = " WHERE " + where
 	}
-	if err := engine.GetInstance().Get(&entity, "SELECT * FROM "+tableName+where, args...); err != nil {
-		return entity, err
+	if err := e.engine.GetInstance().GetContext(ctx, &entity, "SELECT * FROM "+tableName+where, args...); err != nil {
+		return entity, classifyError(err)
 	}
 	return entity, nil
 }
 
 func (e *Entity[entityType]) Select(where string, args ...interface{}) ([]entityType, error) {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return nil, errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+	return e.SelectContext(context.Background(), where, args...)
+}
+
+func (e *Entity[entityType]) SelectContext(ctx context.Context, where string, args ...interface{}) ([]entityType, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return nil, err
 	}
 
 	entity := make([]entityType, 0)
 	if where != "" {
 		where = " WHERE " + where
 	}
-	if err := engine.GetInstance().Select(&entity, "SELECT * FROM "+tableName+where, args...); err != nil {
+	if err := e.engine.GetInstance().SelectContext(ctx, &entity, "SELECT * FROM "+tableName+where, args...); err != nil {
+		return nil, classifyError(err)
+	}
+	return entity, nil
+}
+
+// GetNamed works as Get, but where uses named parameters, f.e. "title = :title AND id IN (:ids)",
+// bound from struct db tags or map[string]interface{}. Slice parameters are expanded.
+// Postgres casts should be escaped as "::::type", sqlx treats "::" as escaped colon.
+func (e *Entity[entityType]) GetNamed(where string, params interface{}) (entityType, error) {
+	return e.GetNamedContext(context.Background(), where, params)
+}
+
+func (e *Entity[entityType]) GetNamedContext(ctx context.Context, where string, params interface{}) (entityType, error) {
+	entity := e.entityObj.(entityType)
+
+	query, args, err := e.namedQuery(where, params)
+	if err != nil {
+		return entity, err
+	}
+	if err := e.engine.GetInstance().GetContext(ctx, &entity, query, args...); err != nil {
+		return entity, classifyError(err)
+	}
+	return entity, nil
+}
+
+// SelectNamed works as Select, but where uses named parameters as GetNamed does
+func (e *Entity[entityType]) SelectNamed(where string, params interface{}) ([]entityType, error) {
+	return e.SelectNamedContext(context.Background(), where, params)
+}
+
+func (e *Entity[entityType]) SelectNamedContext(ctx context.Context, where string, params interface{}) ([]entityType, error) {
+	query, args, err := e.namedQuery(where, params)
+	if err != nil {
 		return nil, err
 	}
+
+	entity := make([]entityType, 0)
+	if err := e.engine.GetInstance().SelectContext(ctx, &entity, query, args...); err != nil {
+		return nil, classifyError(err)
+	}
 	return entity, nil
 }
 
-func (e *Entity[entityType]) FindByID(id int64) (entityType, error) {
+// namedQuery compiles SELECT with named where parameters to the engine placeholders
+func (e *Entity[entityType]) namedQuery(where string, params interface{}) (string, []interface{}, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if where != "" {
+		where = " WHERE " + where
+	}
+	query, args, err := sqlx.Named("SELECT * FROM "+tableName+where, params)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "gpa can't bind named parameters")
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "gpa can't expand named parameters")
+	}
+	return e.engine.GetInstance().Rebind(query), args, nil
+}
+
+// FindByID returns entity by id, lock is optional and requires transaction, f.e. gpa.Lock{Strength: gpa.ForUpdate}
+func (e *Entity[entityType]) FindByID(id int64, lock ...Lock) (entityType, error) {
+	return e.FindByIDContext(context.Background(), id, lock...)
+}
+
+func (e *Entity[entityType]) FindByIDContext(ctx context.Context, id int64, lock ...Lock) (entityType, error) {
 	entity := e.entityObj.(entityType)
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return entity, errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+	tableName, err := e.tableName()
+	if err != nil {
+		return entity, err
 	}
 
-	if err := engine.GetInstance().Get(&entity, "SELECT * FROM "+tableName+" WHERE id = $1", id); err != nil {
+	lockQuery, err := e.getLockQuery(lock)
+	if err != nil {
 		return entity, err
 	}
 
-	return e.withsLazy(entity)
+	if err := e.engine.GetInstance().GetContext(ctx, &entity, "SELECT * FROM "+tableName+" WHERE id = $1"+lockQuery, id); err != nil {
+		return entity, classifyError(err)
+	}
+
+	return e.withsLazy(ctx, entity)
 }
 
-func (e *Entity[entityType]) FindBy(filters []F, p *Pagination) ([]entityType, error) {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return nil, errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+// FindBy lock is optional and requires transaction, f.e. gpa.Lock{Strength: gpa.ForUpdate, Wait: gpa.SkipLocked}
+func (e *Entity[entityType]) FindBy(filters []F, p *Pagination, lock ...Lock) ([]entityType, error) {
+	return e.FindByContext(context.Background(), filters, p, lock...)
+}
+
+func (e *Entity[entityType]) FindByContext(ctx context.Context, filters []F, p *Pagination, lock ...Lock) ([]entityType, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return nil, err
+	}
+
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return nil, err
 	}
 
-	whereElements := " WHERE "
-	paramsCounter := 1
-	values := make([]interface{}, 0)
-	for i := 0; i < len(filters); i++ {
-		filter := filters[i]
-		where := fmt.Sprintf(" %s %s ", filter.FieldName, filter.Sign)
+	pagQuery, err := e.getPagQuery(p)
+	if err != nil {
+		return nil, err
+	}
 
-		whereElements += fmt.Sprintf("%s$%d %s ", where, paramsCounter, filter.Cond)
-		values = append(values, filter.Value)
-		paramsCounter++
+	lockQuery, err := e.getLockQuery(lock)
+	if err != nil {
+		return nil, err
 	}
 
-	query := "SELECT * FROM " + tableName + whereElements + e.getPagQuery(p)
+	query := "SELECT * FROM " + tableName + whereElements + pagQuery + lockQuery
 	entity := make([]entityType, 0)
-	if err := engine.GetInstance().Select(&entity, query, values...); err != nil {
-		return nil, err
+	if err := e.engine.GetInstance().SelectContext(ctx, &entity, query, values...); err != nil {
+		return nil, classifyError(err)
 	}
 	return entity, nil
 }
 
-func (e *Entity[entityType]) FindOneBy(filters []F, p *Pagination) (entityType, error) {
+// FindOneBy lock is optional and requires transaction, f.e. gpa.Lock{Strength: gpa.ForUpdate, Wait: gpa.SkipLocked}
+func (e *Entity[entityType]) FindOneBy(filters []F, p *Pagination, lock ...Lock) (entityType, error) {
+	return e.FindOneByContext(context.Background(), filters, p, lock...)
+}
+
+func (e *Entity[entityType]) FindOneByContext(ctx context.Context, filters []F, p *Pagination, lock ...Lock) (entityType, error) {
 	entity := *new(entityType)
 
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return entity, errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+	tableName, err := e.tableName()
+	if err != nil {
+		return entity, err
 	}
 
-	whereElements := " WHERE "
-	paramsCounter := 1
-	values := make([]interface{}, 0)
-	for i := 0; i < len(filters); i++ {
-		filter := filters[i]
-		where := fmt.Sprintf(" %s %s ", filter.FieldName, filter.Sign)
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return entity, err
+	}
 
-		whereElements += fmt.Sprintf("%s$%d %s ", where, paramsCounter, filter.Cond)
-		values = append(values, filter.Value)
-		paramsCounter++
+	pagQuery, err := e.getPagQuery(p)
+	if err != nil {
+		return entity, err
 	}
 
-	query := "SELECT * FROM " + tableName + whereElements + e.getPagQuery(p)
-	if err := engine.GetInstance().Get(&entity, query, values...); err != nil {
+	lockQuery, err := e.getLockQuery(lock)
+	if err != nil {
 		return entity, err
 	}
+
+	query := "SELECT * FROM " + tableName + whereElements + pagQuery + lockQuery
+	if err := e.engine.GetInstance().GetContext(ctx, &entity, query, values...); err != nil {
+		return entity, classifyError(err)
+	}
 	return entity, nil
 }
 
-func (e *Entity[entityType]) FindAll(p *Pagination) ([]entityType, error) {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return nil, errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+// FindAll lock is optional and requires transaction as in FindBy
+func (e *Entity[entityType]) FindAll(p *Pagination, lock ...Lock) ([]entityType, error) {
+	return e.FindAllContext(context.Background(), p, lock...)
+}
+
+func (e *Entity[entityType]) FindAllContext(ctx context.Context, p *Pagination, lock ...Lock) ([]entityType, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return nil, err
 	}
 
-	var entities []entityType
-	if err := engine.GetInstance().Select(&entities, "SELECT * FROM "+tableName+e.getPagQuery(p)); err != nil {
+	pagQuery, err := e.getPagQuery(p)
+	if err != nil {
 		return nil, err
 	}
-	return e.withLazies(entities)
+
+	lockQuery, err := e.getLockQuery(lock)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []entityType
+	if err := e.engine.GetInstance().SelectContext(ctx, &entities, "SELECT * FROM "+tableName+pagQuery+lockQuery); err != nil {
+		return nil, classifyError(err)
+	}
+	return e.withLazies(ctx, entities)
 }
 
+// Delete removes the row with id, returns ErrNotFound when there is no such row
 func (e *Entity[entityType]) Delete(id int64) error {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
+	return e.DeleteContext(context.Background(), id)
+}
+
+func (e *Entity[entityType]) DeleteContext(ctx context.Context, id int64) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id=%d;", tableName, id)
 
-	_, err := engine.GetInstance().Exec(query)
+	res, err := e.engine.GetInstance().ExecContext(ctx, query)
 	if err != nil {
-		return errors.Wrap(err, "gpa can't remove row with error")
+		return errors.Wrap(classifyError(err), "gpa can't remove row with error")
 	}
-	return nil
+	return checkAffected(res)
 }
 
+// Update updates every column of the row with entity id, returns ErrNotFound when there is no such row
 func (e *Entity[entityType]) Update(entity entityType) error {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
-	}
+	return e.UpdateContext(context.Background(), entity)
+}
 
-	t := reflect.TypeOf(entity)
-	if kind := t.Kind(); kind != reflect.Struct {
-		log.Panicf("should be struct type, %v instead.", kind)
+func (e *Entity[entityType]) UpdateContext(ctx context.Context, entity entityType) error {
+	fields, err := getReflectedData(entity, false)
+	if err != nil {
+		return err
 	}
+	return e.updateColumns(ctx, entity, fields.GetFieldsDb())
+}
+
+func (e *Entity[entityType]) Insert(item interface{}) error {
+	return e.InsertContext(context.Background(), item)
+}
 
-	fields := getReflectedData(entity, false)
-	values := make([]string, 0)
-	for _, f := range fields {
-		values = append(values, fmt.Sprintf("%s = :%s", f.FieldDb, f.FieldDb))
+func (e *Entity[entityType]) InsertContext(ctx context.Context, item interface{}) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
 	}
 
-	queryStr := fmt.Sprintf("UPDATE %s SET %v WHERE id = :id", tableName, strings.Join(values, ","))
+	mdl, err := getReflectedData(item, false)
+	if err != nil {
+		return err
+	}
+	queryStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(quoteIdents(mdl.GetFieldsDb()), ","), ":"+strings.Join(mdl.GetFieldsDb(), ", :"))
 
-	stmt, err := engine.GetInstance().PrepareNamed(queryStr)
+	stmt, err := e.engine.GetInstance().PrepareNamedContext(ctx, queryStr)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(entity)
-	return err
+	_, err = stmt.ExecContext(ctx, item)
+	return classifyError(err)
 }
 
-func (e *Entity[entityType]) Insert(item interface{}) error {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return errors.New(fmt.Sprintf("entity %s wasn't configurate ", reflect.TypeOf(e.entityObj)))
-	}
+// InsertReturning inserts item and populates it back with the inserted row,
+// so generated id and columns filled by the database are available after the call.
+func (e *Entity[entityType]) InsertReturning(item *entityType) error {
+	return e.InsertReturningContext(context.Background(), item)
+}
 
-	t := reflect.TypeOf(item)
-	if kind := t.Kind(); kind != reflect.Struct {
-		log.Panicf("should be struct type, %v instead.", kind)
+func (e *Entity[entityType]) InsertReturningContext(ctx context.Context, item *entityType) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
 	}
 
-	mdl := getReflectedData(item, false)
-	queryStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(mdl.GetFieldsDb(), ","), ":"+strings.Join(mdl.GetFieldsDb(), ", :"))
+	mdl, err := getReflectedData(item, false)
+	if err != nil {
+		return err
+	}
+	queryStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING *", tableName, strings.Join(quoteIdents(mdl.GetFieldsDb()), ","), ":"+strings.Join(mdl.GetFieldsDb(), ", :"))
 
-	stmt, err := engine.GetInstance().PrepareNamed(queryStr)
+	stmt, err := e.engine.GetInstance().PrepareNamedContext(ctx, queryStr)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(item)
-	return err
+	return classifyError(stmt.QueryRowxContext(ctx, item).StructScan(item))
 }
 
+// Inserts inserts all items with one query, items are populated back with inserted rows in the same order.
 func (e *Entity[entityType]) Inserts(items []entityType) error {
-	tableName, ok := engine.GetTableName(e.entityObj)
-	if !ok {
-		return errors.New(fmt.Sprintf("should be struct type, %v instead.", reflect.TypeOf(e.entityObj)))
+	return e.InsertsContext(context.Background(), items)
+}
+
+func (e *Entity[entityType]) InsertsContext(ctx context.Context, items []entityType) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	query, args, err := e.insertsQuery(items)
+	if err != nil {
+		return err
+	}
+	return e.queryReturning(ctx, query+" RETURNING *", args, items)
+}
+
+// insertsQuery builds multi-row INSERT query for the items, bound to the engine placeholders
+func (e *Entity[entityType]) insertsQuery(items []entityType) (string, []interface{}, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return "", nil, err
 	}
 
 	item := items[0]
-	mdl := getReflectedData(item, false)
+	mdl, err := getReflectedData(item, false)
+	if err != nil {
+		return "", nil, err
+	}
 
 	rows := make([]interface{}, 0)
 	queryArgs := make([]string, 0)
-	queryStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", tableName, strings.Join(mdl.GetFieldsDb(), ","))
+	queryStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", tableName, strings.Join(quoteIdents(mdl.GetFieldsDb()), ","))
 
 	for _, i := range items {
 		queryArgs = append(queryArgs, "(?)")
@@ -218,8 +381,23 @@ func (e *Entity[entityType]) Inserts(items []entityType) error {
 	}
 	query, args, err := sqlx.In(queryStr+strings.Join(queryArgs, ", "), rows...)
 	if err != nil {
-		return err
+		return "", nil, err
+	}
+	return e.engine.GetInstance().Rebind(query), args, nil
+}
+
+// queryReturning scans rows returned by the query into items in the same order
+func (e *Entity[entityType]) queryReturning(ctx context.Context, query string, args []interface{}, items []entityType) error {
+	rows, err := e.engine.GetInstance().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return classifyError(err)
+	}
+	defer rows.Close()
+
+	for i := 0; rows.Next() && i < len(items); i++ {
+		if err := rows.StructScan(&items[i]); err != nil {
+			return err
+		}
 	}
-	_, err = engine.GetInstance().Exec(engine.GetInstance().Rebind(query), args...)
-	return err
+	return classifyError(rows.Err())
 }
@@ -0,0 +1,176 @@
+package gpa
+
+import (
+	"context"
+)
+
+// QueryBuilder chainable query on the entity table, compiled to parameterized SQL.
+// Columns are validated against entity db tags when the query is built by Find, First or Count.
+//
+//	docs, err := gpa.From[Document]().Where("views", gpa.More, 10).OrderBy("title", gpa.Desc).Limit(20).Find()
+type QueryBuilder[entityType any] struct {
+	entity  *Entity[entityType]
+	filters []F
+	orders  []Order
+	limit   int64
+	offset  int64
+	locks   []Lock
+}
+
+// Query starts new query builder on the entity
+func (e *Entity[entityType]) Query() *QueryBuilder[entityType] {
+	return &QueryBuilder[entityType]{entity: e}
+}
+
+func (e *Entity[entityType]) Where(column string, sign Sign, value interface{}) *QueryBuilder[entityType] {
+	return e.Query().Where(column, sign, value)
+}
+
+func (e *Entity[entityType]) OrderBy(column string, direction Direction) *QueryBuilder[entityType] {
+	return e.Query().OrderBy(column, direction)
+}
+
+func (e *Entity[entityType]) Limit(limit int64) *QueryBuilder[entityType] {
+	return e.Query().Limit(limit)
+}
+
+func (e *Entity[entityType]) Offset(offset int64) *QueryBuilder[entityType] {
+	return e.Query().Offset(offset)
+}
+
+// Where adds condition joined to the previous one with AND
+func (q *QueryBuilder[entityType]) Where(column string, sign Sign, value interface{}) *QueryBuilder[entityType] {
+	return q.addFilter(AND, F{FieldName: column, Sign: sign, Value: value})
+}
+
+// OrWhere adds condition joined to the previous one with OR
+func (q *QueryBuilder[entityType]) OrWhere(column string, sign Sign, value interface{}) *QueryBuilder[entityType] {
+	return q.addFilter(OR, F{FieldName: column, Sign: sign, Value: value})
+}
+
+// WhereF adds filter or filters group joined to the previous condition with AND
+func (q *QueryBuilder[entityType]) WhereF(f F) *QueryBuilder[entityType] {
+	return q.addFilter(AND, f)
+}
+
+// OrWhereF adds filter or filters group joined to the previous condition with OR
+func (q *QueryBuilder[entityType]) OrWhereF(f F) *QueryBuilder[entityType] {
+	return q.addFilter(OR, f)
+}
+
+func (q *QueryBuilder[entityType]) OrderBy(column string, direction Direction) *QueryBuilder[entityType] {
+	q.orders = append(q.orders, Order{Column: column, Direction: direction})
+	return q
+}
+
+func (q *QueryBuilder[entityType]) Limit(limit int64) *QueryBuilder[entityType] {
+	q.limit = limit
+	return q
+}
+
+func (q *QueryBuilder[entityType]) Offset(offset int64) *QueryBuilder[entityType] {
+	q.offset = offset
+	return q
+}
+
+// Lock adds row locking clause to Find and First, the entity should be from FromTx
+func (q *QueryBuilder[entityType]) Lock(lock Lock) *QueryBuilder[entityType] {
+	q.locks = []Lock{lock}
+	return q
+}
+
+// Find returns all matched entities, lazy entities are fetched as in FindAll
+func (q *QueryBuilder[entityType]) Find() ([]entityType, error) {
+	return q.FindContext(context.Background())
+}
+
+func (q *QueryBuilder[entityType]) FindContext(ctx context.Context) ([]entityType, error) {
+	query, values, err := q.build("*", &Pagination{Limit: q.limit, Offset: q.offset, Order: q.orders})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entityType, 0)
+	if err := q.entity.engine.GetInstance().SelectContext(ctx, &entities, query, values...); err != nil {
+		return nil, classifyError(err)
+	}
+	return q.entity.withLazies(ctx, entities)
+}
+
+// First returns the first matched entity or ErrNotFound
+func (q *QueryBuilder[entityType]) First() (entityType, error) {
+	return q.FirstContext(context.Background())
+}
+
+func (q *QueryBuilder[entityType]) FirstContext(ctx context.Context) (entityType, error) {
+	entity := *new(entityType)
+	query, values, err := q.build("*", &Pagination{Limit: 1, Offset: q.offset, Order: q.orders})
+	if err != nil {
+		return entity, err
+	}
+
+	if err := q.entity.engine.GetInstance().GetContext(ctx, &entity, query, values...); err != nil {
+		return entity, classifyError(err)
+	}
+	return q.entity.withsLazy(ctx, entity)
+}
+
+// Count returns number of matched rows, ordering, pagination and lock are ignored
+func (q *QueryBuilder[entityType]) Count() (int64, error) {
+	return q.CountContext(context.Background())
+}
+
+func (q *QueryBuilder[entityType]) CountContext(ctx context.Context) (int64, error) {
+	query, values, err := q.buildSelect("COUNT(*)")
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := q.entity.engine.GetInstance().GetContext(ctx, &count, query, values...); err != nil {
+		return 0, classifyError(err)
+	}
+	return count, nil
+}
+
+// addFilter adds filter, its columns are validated when the query is built
+func (q *QueryBuilder[entityType]) addFilter(cond Condition, f F) *QueryBuilder[entityType] {
+	if len(q.filters) > 0 {
+		q.filters[len(q.filters)-1].Cond = cond
+	}
+	q.filters = append(q.filters, f)
+	return q
+}
+
+// buildSelect builds SELECT of columns with WHERE clause
+func (q *QueryBuilder[entityType]) buildSelect(columns string) (string, []interface{}, error) {
+	tableName, err := q.entity.tableName()
+	if err != nil {
+		return "", nil, err
+	}
+
+	whereElements, values, err := q.entity.getWhereQuery(q.filters, 1)
+	if err != nil {
+		return "", nil, err
+	}
+	return "SELECT " + columns + " FROM " + tableName + whereElements, values, nil
+}
+
+// build builds SELECT of columns with WHERE, ORDER BY, pagination clauses of p and row locking clause
+func (q *QueryBuilder[entityType]) build(columns string, p *Pagination) (string, []interface{}, error) {
+	query, values, err := q.buildSelect(columns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pagQuery, err := q.entity.getPagQuery(p)
+	if err != nil {
+		return "", nil, err
+	}
+
+	lockQuery, err := q.entity.getLockQuery(q.locks)
+	if err != nil {
+		return "", nil, err
+	}
+	return query + pagQuery + lockQuery, values, nil
+}
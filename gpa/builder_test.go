@@ -0,0 +1,78 @@
+package gpa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryBuilderSQL(t *testing.T) {
+	entity := newTestEntity[bulkDocument]("documents")
+	tests := []struct {
+		name       string
+		query      *QueryBuilder[bulkDocument]
+		wantQuery  string
+		wantValues []interface{}
+	}{
+		{
+			name:       "where and or where",
+			query:      entity.Where("views", More, 10).OrWhere("title", Equal, "doc1"),
+			wantQuery:  `SELECT * FROM "documents" WHERE "views" > $1 OR "title" = $2`,
+			wantValues: []interface{}{10, "doc1"},
+		},
+		{
+			name: "nested groups",
+			query: entity.Where("id", In, []int64{1, 2}).
+				WhereF(Or(
+					F{FieldName: "title", Sign: Equal, Value: "a"},
+					And(F{FieldName: "views", Sign: Between, Value: []int{1, 5}}, Not(F{FieldName: "title", Sign: Like, Value: "b%"})),
+				)).
+				OrWhere("views", IsNull, nil).
+				OrWhere("views", Any, []int{7}),
+			wantQuery: `SELECT * FROM "documents" WHERE "id" IN ($1, $2) AND ("title" = $3 OR ("views" BETWEEN $4 AND $5 AND NOT ("title" LIKE $6))) ` +
+				`OR "views" IS NULL OR "views" = ANY($7)`,
+			wantValues: []interface{}{int64(1), int64(2), "a", 1, 5, "b%", []int{7}},
+		},
+		{
+			name:       "order and pagination",
+			query:      entity.Where("views", More, 10).OrderBy("title", Desc).Limit(20).Offset(40),
+			wantQuery:  `SELECT * FROM "documents" WHERE "views" > $1 ORDER BY "title" DESC LIMIT 20 OFFSET 40`,
+			wantValues: []interface{}{10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.query
+			query, values, err := q.build("*", &Pagination{Limit: q.limit, Offset: q.offset, Order: q.orders})
+			if err != nil {
+				t.Fatalf("build error = %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("build query =\n%s\nwant\n%s", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("build values = %#v, want %#v", values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestUpdateByQueryPlaceholders(t *testing.T) {
+	entity := newTestEntity[bulkDocument]("documents")
+	query, args, err := entity.updateByQuery([]F{
+		{FieldName: "id", Sign: In, Value: []int64{3, 4}},
+		Or(F{FieldName: "views", Sign: Less, Value: 10}, F{FieldName: "title", Sign: Equal, Value: ""}),
+	}, map[string]any{"views": 0, "title": "draft"}, nil)
+	if err != nil {
+		t.Fatalf("updateByQuery error = %v", err)
+	}
+
+	wantQuery := `UPDATE "documents" SET "title" = $1, "views" = $2 WHERE "id" IN ($3, $4) AND ("views" < $5 OR "title" = $6)`
+	if query != wantQuery {
+		t.Errorf("updateByQuery query =\n%s\nwant\n%s", query, wantQuery)
+	}
+	wantArgs := []interface{}{"draft", 0, int64(3), int64(4), 10, ""}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("updateByQuery args = %#v, want %#v", args, wantArgs)
+	}
+}
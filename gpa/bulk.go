@@ -0,0 +1,96 @@
+package gpa
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+type bulkOptions struct {
+	allowFullTable bool
+}
+
+type BulkOption func(o *bulkOptions)
+
+// AllowFullTable allows DeleteBy and UpdateBy to change every row when filters are empty
+// or always true, f.e. []gpa.F{gpa.And()}
+func AllowFullTable() BulkOption {
+	return func(o *bulkOptions) {
+		o.allowFullTable = true
+	}
+}
+
+// checkBulkOptions refuses filters matching every row unless AllowFullTable is passed
+func checkBulkOptions(filtersTruth truth, opts []BulkOption) error {
+	o := bulkOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if filtersTruth == alwaysTrue && !o.allowFullTable {
+		return ErrFullTable
+	}
+	return nil
+}
+
+// DeleteBy removes rows matched by filters and returns number of removed rows
+func (e *Entity[entityType]) DeleteBy(filters []F, opts ...BulkOption) (int64, error) {
+	return e.DeleteByContext(context.Background(), filters, opts...)
+}
+
+func (e *Entity[entityType]) DeleteByContext(ctx context.Context, filters []F, opts ...BulkOption) (int64, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return 0, err
+	}
+
+	whereElements, values, filtersTruth, err := e.whereQuery(filters, 1)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkBulkOptions(filtersTruth, opts); err != nil {
+		return 0, err
+	}
+	res, err := e.engine.GetInstance().ExecContext(ctx, "DELETE FROM "+tableName+whereElements, values...)
+	if err != nil {
+		return 0, errors.Wrap(classifyError(err), "gpa can't remove rows with error")
+	}
+	return res.RowsAffected()
+}
+
+// UpdateBy sets values to rows matched by filters and returns number of updated rows
+func (e *Entity[entityType]) UpdateBy(filters []F, values map[string]any, opts ...BulkOption) (int64, error) {
+	return e.UpdateByContext(context.Background(), filters, values, opts...)
+}
+
+func (e *Entity[entityType]) UpdateByContext(ctx context.Context, filters []F, values map[string]any, opts ...BulkOption) (int64, error) {
+	query, args, err := e.updateByQuery(filters, values, opts)
+	if err != nil {
+		return 0, err
+	}
+	res, err := e.engine.GetInstance().ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, classifyError(err)
+	}
+	return res.RowsAffected()
+}
+
+// updateByQuery builds UPDATE query, WHERE placeholders are numbered after SET ones
+func (e *Entity[entityType]) updateByQuery(filters []F, values map[string]any, opts []BulkOption) (string, []interface{}, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sets, args, err := e.setClause(values, 1)
+	if err != nil {
+		return "", nil, err
+	}
+	whereElements, whereValues, filtersTruth, err := e.whereQuery(filters, len(args)+1)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := checkBulkOptions(filtersTruth, opts); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("UPDATE %s SET %s%s", tableName, sets, whereElements), append(args, whereValues...), nil
+}
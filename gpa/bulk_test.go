@@ -0,0 +1,84 @@
+package gpa
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"testing"
+)
+
+type bulkDocument struct {
+	ID    int64  `db:"id"`
+	Title string `db:"title"`
+	Views int64  `db:"views"`
+}
+
+// newTestEntity returns entity bound to the engine without database connection, its table is registered as initialized
+func newTestEntity[entityType any](tableName string) *Entity[entityType] {
+	e := NewIndependentEngine(sqlx.NewDb(nil, "pgx"), Config{})
+	entity := *new(entityType)
+	e.registry.setTableName(entity, tableName)
+	e.registry.setInitialized(reflect.TypeOf(entity))
+	return FromEngine[entityType](e)
+}
+
+func TestBulkRefusesFiltersMatchingEveryRow(t *testing.T) {
+	views := F{FieldName: "views", Sign: More, Value: 10}
+	tests := []struct {
+		name    string
+		filters []F
+	}{
+		{name: "empty", filters: nil},
+		{name: "empty and", filters: []F{And()}},
+		{name: "not empty or", filters: []F{Not(Or())}},
+		{name: "or with empty and", filters: []F{Or(views, And())}},
+		{name: "empty not in", filters: []F{{FieldName: "id", Sign: NotIn, Value: []int64{}}}},
+		{name: "not empty in", filters: []F{Not(F{FieldName: "id", Sign: In, Value: []int64{}})}},
+		{name: "top level or with empty and", filters: []F{{FieldName: "views", Sign: More, Value: 10, Cond: OR}, And()}},
+	}
+
+	entity := newTestEntity[bulkDocument]("documents")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := entity.DeleteBy(tt.filters); !errors.Is(err, ErrFullTable) {
+				t.Errorf("DeleteBy error = %v, want ErrFullTable", err)
+			}
+			if _, err := entity.UpdateBy(tt.filters, map[string]any{"title": "draft"}); !errors.Is(err, ErrFullTable) {
+				t.Errorf("UpdateBy error = %v, want ErrFullTable", err)
+			}
+		})
+	}
+}
+
+func TestWhereQueryTruth(t *testing.T) {
+	views := F{FieldName: "views", Sign: More, Value: 10}
+	tests := []struct {
+		name    string
+		filters []F
+		want    truth
+	}{
+		{name: "comparison", filters: []F{views}, want: unknown},
+		{name: "and with empty and", filters: []F{views, And()}, want: unknown},
+		{name: "empty and before or", filters: []F{{FieldName: "views", Sign: More, Value: 10}, {FieldName: "id", Sign: Equal, Value: 1, Cond: OR}, Not(And())}, want: unknown},
+		{name: "not empty and", filters: []F{Not(And())}, want: alwaysFalse},
+		{name: "empty or", filters: []F{Or()}, want: alwaysFalse},
+		{name: "and with empty or", filters: []F{And(views, Or())}, want: alwaysFalse},
+		{name: "empty in", filters: []F{{FieldName: "id", Sign: In, Value: []int64{}}}, want: alwaysFalse},
+		{name: "and with empty not in", filters: []F{views, {FieldName: "id", Sign: NotIn, Value: []int64{}}}, want: unknown},
+		{name: "not in with values", filters: []F{{FieldName: "id", Sign: NotIn, Value: []int64{1}}}, want: unknown},
+		{name: "nested groups", filters: []F{Or(And(), Not(Or(views)))}, want: alwaysTrue},
+	}
+
+	entity := newTestEntity[bulkDocument]("documents")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, got, err := entity.whereQuery(tt.filters, 1)
+			if err != nil {
+				t.Fatalf("whereQuery error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("whereQuery truth = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,33 @@
 package gpa
 
+// Pagination rows are ordered by Order, or by id when Limit or Offset are set without Order
 type Pagination struct {
 	Limit  int64
 	Offset int64
+	Order  []Order
 }
 
 type Sign string
 
 const (
 	Equal     Sign = "="
+	NotEqual  Sign = "<>"
 	MoreEqual Sign = ">="
 	LessEqual Sign = "<="
 	More      Sign = ">"
 	Less      Sign = "<"
+	Like      Sign = "LIKE"
+	ILike     Sign = "ILIKE"
+	// In and NotIn expect slice value, each element is passed as separate parameter
+	In    Sign = "IN"
+	NotIn Sign = "NOT IN"
+	// IsNull and IsNotNull don't use value
+	IsNull    Sign = "IS NULL"
+	IsNotNull Sign = "IS NOT NULL"
+	// Between expects slice value with two elements
+	Between Sign = "BETWEEN"
+	// Any expects slice value passed as Postgres array parameter
+	Any Sign = "= ANY"
 )
 
 type Condition string
@@ -23,9 +38,77 @@ const (
 )
 
 // F Filter
+// Cond joins the filter with the next one, AND is used when it's empty.
+// Groups of filters are built with And, Or and Not, f.e. (a = 1 OR b = 2) AND c > 3:
+//
+//	[]gpa.F{gpa.Or(a, b), c}
 type F struct {
 	FieldName string
 	Sign      Sign
 	Value     interface{}
 	Cond      Condition
+
+	// group nested filters joined with groupCond, filter is a group when groupCond isn't empty
+	group     []F
+	groupCond Condition
+	not       bool
+}
+
+// And groups filters joined with AND, Cond of grouped filters is ignored
+func And(filters ...F) F {
+	return F{group: filters, groupCond: AND}
+}
+
+// Or groups filters joined with OR, Cond of grouped filters is ignored
+func Or(filters ...F) F {
+	return F{group: filters, groupCond: OR}
+}
+
+// Not negates the filter
+func Not(filter F) F {
+	return F{group: []F{filter}, groupCond: AND, not: true}
+}
+
+type Direction string
+
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+type Nulls string
+
+const (
+	NullsFirst Nulls = "NULLS FIRST"
+	NullsLast  Nulls = "NULLS LAST"
+)
+
+// Order sorting by the column, ASC is used when Direction is empty
+type Order struct {
+	Column    string
+	Direction Direction
+	Nulls     Nulls
+}
+
+type LockStrength string
+
+const (
+	ForUpdate      LockStrength = "FOR UPDATE"
+	ForNoKeyUpdate LockStrength = "FOR NO KEY UPDATE"
+	ForShare       LockStrength = "FOR SHARE"
+	ForKeyShare    LockStrength = "FOR KEY SHARE"
+)
+
+type LockWait string
+
+const (
+	NoWait     LockWait = "NOWAIT"
+	SkipLocked LockWait = "SKIP LOCKED"
+)
+
+// Lock row locking clause of the select, rows are locked until the transaction ends.
+// Lock waits for locked rows when Wait is empty.
+type Lock struct {
+	Strength LockStrength
+	Wait     LockWait
 }
@@ -0,0 +1,321 @@
+package gpa
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CursorPage page of keyset pagination.
+// Next and Prev are opaque cursors for FindPage, empty when there is no such page.
+type CursorPage[entityType any] struct {
+	Items []entityType
+	Next  string
+	Prev  string
+}
+
+// cursor position of the row in the keyset, Values are order columns values of the row,
+// Order is the page order the cursor was issued for
+type cursor struct {
+	Values   []cursorValue `json:"v"`
+	Order    string        `json:"o"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// cursorValue driver value with its type, so it's decoded back to the same Go type
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+// FindPage returns page of size rows after the cursor, empty cursor returns the first page.
+// Rows are ordered by order columns with id appended as tie-breaker and are fetched with seek predicate,
+// f.e. WHERE (title, id) > ($1, $2), instead of OFFSET. Order Nulls isn't supported and order columns
+// should be NOT NULL, error is returned when the page row has NULL order value.
+// Rows aren't locked, FindBy with Lock and id filters could be used to lock the page rows.
+// Cursor should be used with the same order it was issued for.
+func (e *Entity[entityType]) FindPage(filters []F, pageCursor string, size int, order ...Order) (CursorPage[entityType], error) {
+	return e.FindPageContext(context.Background(), filters, pageCursor, size, order...)
+}
+
+func (e *Entity[entityType]) FindPageContext(ctx context.Context, filters []F, pageCursor string, size int, order ...Order) (CursorPage[entityType], error) {
+	page := CursorPage[entityType]{Items: make([]entityType, 0)}
+	if size <= 0 {
+		return page, errors.Errorf("gpa page size should be positive, got %d", size)
+	}
+	tableName, err := e.tableName()
+	if err != nil {
+		return page, err
+	}
+
+	orders, err := keysetOrder(order)
+	if err != nil {
+		return page, err
+	}
+	c, err := decodeCursor(pageCursor)
+	if err != nil {
+		return page, err
+	}
+	if pageCursor != "" && (c.Order != orderKey(orders) || len(c.Values) != len(orders)) {
+		return page, errors.New("gpa cursor doesn't match page order")
+	}
+
+	queryOrders := orders
+	if c.Backward {
+		queryOrders = reverseOrder(orders)
+	}
+
+	w, err := e.newWhereBuilder(1)
+	if err != nil {
+		return page, err
+	}
+	conditions := make([]string, 0)
+	if len(filters) > 0 {
+		filtersConditions, _, err := w.conditions(filters)
+		if err != nil {
+			return page, err
+		}
+		conditions = append(conditions, "("+filtersConditions+")")
+	}
+	if len(c.Values) > 0 {
+		values, err := c.decodeValues()
+		if err != nil {
+			return page, err
+		}
+		seek, err := w.seek(queryOrders, values)
+		if err != nil {
+			return page, err
+		}
+		conditions = append(conditions, seek)
+	}
+
+	whereElements := ""
+	if len(conditions) > 0 {
+		whereElements = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	orderQuery, err := e.getOrderQuery(queryOrders)
+	if err != nil {
+		return page, err
+	}
+
+	query := "SELECT * FROM " + tableName + whereElements + orderQuery + " LIMIT " + strconv.Itoa(size+1)
+	items := make([]entityType, 0)
+	if err := e.engine.GetInstance().SelectContext(ctx, &items, query, w.values...); err != nil {
+		return page, classifyError(err)
+	}
+
+	hasMore := len(items) > size
+	if hasMore {
+		items = items[:size]
+	}
+	if c.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if len(items) > 0 {
+		if hasMore || c.Backward {
+			if page.Next, err = e.encodeCursor(items[len(items)-1], orders, false); err != nil {
+				return page, err
+			}
+		}
+		if (hasMore && c.Backward) || (!c.Backward && pageCursor != "") {
+			if page.Prev, err = e.encodeCursor(items[0], orders, true); err != nil {
+				return page, err
+			}
+		}
+	}
+
+	page.Items, err = e.withLazies(ctx, items)
+	return page, err
+}
+
+// seek builds keyset predicate of rows after values in orders.
+// Row comparison is used when all columns have the same direction, so it could be served by an index.
+func (w *whereBuilder) seek(orders []Order, values []interface{}) (string, error) {
+	columns := make([]string, 0)
+	sameDirection := true
+	for _, o := range orders {
+		column, err := w.column(o.Column)
+		if err != nil {
+			return "", err
+		}
+		columns = append(columns, column)
+		sameDirection = sameDirection && o.Direction == orders[0].Direction
+	}
+
+	if sameDirection {
+		placeholders := make([]string, 0)
+		for _, v := range values {
+			placeholders = append(placeholders, w.placeholder(v))
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), seekSign(orders[0]), strings.Join(placeholders, ", ")), nil
+	}
+
+	// (a > $1) OR (a = $2 AND b < $3) ...
+	parts := make([]string, 0)
+	for i := range orders {
+		conditions := make([]string, 0)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, columns[j]+" = "+w.placeholder(values[j]))
+		}
+		conditions = append(conditions, columns[i]+" "+seekSign(orders[i])+" "+w.placeholder(values[i]))
+		parts = append(parts, "("+strings.Join(conditions, " AND ")+")")
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", nil
+}
+
+func seekSign(o Order) string {
+	if o.Direction == Desc {
+		return string(Less)
+	}
+	return string(More)
+}
+
+// keysetOrder normalizes order directions and appends id, so every row position is unique.
+// Nulls is refused, NULL values can't be compared by seek predicate.
+func keysetOrder(order []Order) ([]Order, error) {
+	orders := make([]Order, 0, len(order)+1)
+	hasID := false
+	for _, o := range order {
+		if o.Nulls != "" {
+			return nil, errors.Errorf("gpa keyset pagination doesn't support %s of column %s", o.Nulls, o.Column)
+		}
+		if o.Direction == "" {
+			o.Direction = Asc
+		}
+		hasID = hasID || o.Column == "id"
+		orders = append(orders, o)
+	}
+	if !hasID {
+		orders = append(orders, Order{Column: "id", Direction: Asc})
+	}
+	return orders, nil
+}
+
+// orderKey identifies keyset order, so cursor isn't used with another order
+func orderKey(orders []Order) string {
+	items := make([]string, 0, len(orders))
+	for _, o := range orders {
+		items = append(items, strings.TrimSpace(strconv.Quote(o.Column)+" "+string(o.Direction)+" "+string(o.Nulls)))
+	}
+	return strings.Join(items, ",")
+}
+
+// reverseOrder flips directions, used for fetching the previous page
+func reverseOrder(orders []Order) []Order {
+	reversed := make([]Order, 0, len(orders))
+	for _, o := range orders {
+		if o.Direction == Desc {
+			o.Direction = Asc
+		} else {
+			o.Direction = Desc
+		}
+		reversed = append(reversed, o)
+	}
+	return reversed
+}
+
+// encodeCursor encodes order columns values of the item to the opaque cursor
+func (e *Entity[entityType]) encodeCursor(item entityType, orders []Order, backward bool) (string, error) {
+	fields, err := getReflectedData(item, true)
+	if err != nil {
+		return "", err
+	}
+
+	c := cursor{Order: orderKey(orders), Backward: backward}
+	v := reflect.ValueOf(item)
+	for _, o := range orders {
+		value, err := driver.DefaultParameterConverter.ConvertValue(v.FieldByName(fields.GetDataByDBTag(o.Column).FieldName).Interface())
+		if err != nil {
+			return "", errors.Wrapf(err, "gpa can't encode cursor column %s", o.Column)
+		}
+		if value == nil {
+			return "", errors.Errorf("gpa can't encode cursor column %s with NULL value, keyset order columns should be NOT NULL", o.Column)
+		}
+		cv, err := newCursorValue(value)
+		if err != nil {
+			return "", errors.Wrapf(err, "gpa can't encode cursor column %s", o.Column)
+		}
+		c.Values = append(c.Values, cv)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string) (cursor, error) {
+	c := cursor{}
+	if s == "" {
+		return c, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.Wrap(err, "gpa invalid cursor")
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, errors.Wrap(err, "gpa invalid cursor")
+	}
+	return c, nil
+}
+
+func newCursorValue(value driver.Value) (cursorValue, error) {
+	switch v := value.(type) {
+	case int64:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case bool:
+		return cursorValue{Type: "b", Value: strconv.FormatBool(v)}, nil
+	case []byte:
+		return cursorValue{Type: "y", Value: base64.StdEncoding.EncodeToString(v)}, nil
+	case string:
+		return cursorValue{Type: "s", Value: v}, nil
+	case time.Time:
+		return cursorValue{Type: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	}
+	return cursorValue{}, errors.Errorf("unsupported value type %T", value)
+}
+
+func (c cursor) decodeValues() ([]interface{}, error) {
+	values := make([]interface{}, 0, len(c.Values))
+	for _, cv := range c.Values {
+		var (
+			value interface{}
+			err   error
+		)
+		switch cv.Type {
+		case "i":
+			value, err = strconv.ParseInt(cv.Value, 10, 64)
+		case "f":
+			value, err = strconv.ParseFloat(cv.Value, 64)
+		case "b":
+			value, err = strconv.ParseBool(cv.Value)
+		case "y":
+			value, err = base64.StdEncoding.DecodeString(cv.Value)
+		case "s":
+			value = cv.Value
+		case "t":
+			value, err = time.Parse(time.RFC3339Nano, cv.Value)
+		default:
+			err = errors.Errorf("unknown value type %q", cv.Type)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "gpa invalid cursor")
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,202 @@
+package gpa
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type cursorEvent struct {
+	ID      int64     `db:"id"`
+	Title   string    `db:"title"`
+	Views   int64     `db:"views"`
+	Created time.Time `db:"created"`
+}
+
+func TestSeek(t *testing.T) {
+	tests := []struct {
+		name       string
+		orders     []Order
+		values     []interface{}
+		wantQuery  string
+		wantValues []interface{}
+	}{
+		{
+			name:       "same direction",
+			orders:     keyset(t, Order{Column: "title"}),
+			values:     []interface{}{"a", int64(7)},
+			wantQuery:  `("title", "id") > ($2, $3)`,
+			wantValues: []interface{}{"a", int64(7)},
+		},
+		{
+			name:       "same direction desc",
+			orders:     keyset(t, Order{Column: "views", Direction: Desc}, Order{Column: "id", Direction: Desc}),
+			values:     []interface{}{int64(10), int64(7)},
+			wantQuery:  `("views", "id") < ($2, $3)`,
+			wantValues: []interface{}{int64(10), int64(7)},
+		},
+		{
+			name:       "mixed directions",
+			orders:     keyset(t, Order{Column: "views", Direction: Desc}, Order{Column: "title"}),
+			values:     []interface{}{int64(10), "a", int64(7)},
+			wantQuery:  `(("views" < $2) OR ("views" = $3 AND "title" > $4) OR ("views" = $5 AND "title" = $6 AND "id" > $7))`,
+			wantValues: []interface{}{int64(10), int64(10), "a", int64(10), "a", int64(7)},
+		},
+		{
+			name:       "mixed directions reversed",
+			orders:     reverseOrder(keyset(t, Order{Column: "views", Direction: Desc}, Order{Column: "title"})),
+			values:     []interface{}{int64(10), "a", int64(7)},
+			wantQuery:  `(("views" > $2) OR ("views" = $3 AND "title" < $4) OR ("views" = $5 AND "title" = $6 AND "id" < $7))`,
+			wantValues: []interface{}{int64(10), int64(10), "a", int64(10), "a", int64(7)},
+		},
+	}
+
+	entity := newTestEntity[cursorEvent]("events")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := entity.newWhereBuilder(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			query, err := w.seek(tt.orders, tt.values)
+			if err != nil {
+				t.Fatalf("seek error = %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("seek query =\n%s\nwant\n%s", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(w.values, tt.wantValues) {
+				t.Errorf("seek values = %#v, want %#v", w.values, tt.wantValues)
+			}
+		})
+	}
+}
+
+// keyset returns keyset order of order columns, test fails when it's refused
+func keyset(t *testing.T, order ...Order) []Order {
+	t.Helper()
+	orders, err := keysetOrder(order)
+	if err != nil {
+		t.Fatalf("keysetOrder error = %v", err)
+	}
+	return orders
+}
+
+func TestKeysetOrder(t *testing.T) {
+	got := keyset(t, Order{Column: "title"}, Order{Column: "views", Direction: Desc})
+	want := []Order{{Column: "title", Direction: Asc}, {Column: "views", Direction: Desc}, {Column: "id", Direction: Asc}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetOrder = %v, want %v", got, want)
+	}
+
+	got = reverseOrder(got)
+	want = []Order{{Column: "title", Direction: Desc}, {Column: "views", Direction: Asc}, {Column: "id", Direction: Desc}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reverseOrder = %v, want %v", got, want)
+	}
+
+	got = keyset(t, Order{Column: "id", Direction: Desc})
+	want = []Order{{Column: "id", Direction: Desc}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetOrder with id = %v, want %v", got, want)
+	}
+
+	if _, err := keysetOrder([]Order{{Column: "views", Nulls: NullsLast}}); err == nil {
+		t.Error("keysetOrder with Nulls error = nil, want error")
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.FixedZone("EET", 2*60*60))
+	item := cursorEvent{ID: 42, Title: `a "quoted", title`, Created: created}
+	orders := keyset(t, Order{Column: "created", Direction: Desc}, Order{Column: "title"})
+
+	entity := newTestEntity[cursorEvent]("events")
+	encoded, err := entity.encodeCursor(item, orders, true)
+	if err != nil {
+		t.Fatalf("encodeCursor error = %v", err)
+	}
+
+	c, err := decodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("decodeCursor error = %v", err)
+	}
+	if !c.Backward {
+		t.Error("decoded cursor isn't backward")
+	}
+	if c.Order != orderKey(orders) {
+		t.Errorf("decoded cursor order = %s, want %s", c.Order, orderKey(orders))
+	}
+
+	values, err := c.decodeValues()
+	if err != nil {
+		t.Fatalf("decodeValues error = %v", err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("decoded %d values, want 3", len(values))
+	}
+	if got, ok := values[0].(time.Time); !ok || !got.Equal(created) {
+		t.Errorf("decoded time = %#v, want %v", values[0], created)
+	}
+	if values[1] != item.Title {
+		t.Errorf("decoded string = %#v, want %q", values[1], item.Title)
+	}
+	if values[2] != int64(42) {
+		t.Errorf("decoded int = %#v, want 42", values[2])
+	}
+}
+
+func TestFindPageRejectsCursor(t *testing.T) {
+	entity := newTestEntity[cursorEvent]("events")
+	byTitle, err := entity.encodeCursor(cursorEvent{ID: 1, Title: "a"}, keyset(t, Order{Column: "title"}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		order  []Order
+	}{
+		{name: "another column", cursor: byTitle, order: []Order{{Column: "views"}}},
+		{name: "another direction", cursor: byTitle, order: []Order{{Column: "title", Direction: Desc}}},
+		{name: "invalid base64", cursor: "not a cursor!", order: nil},
+		{name: "invalid value type", cursor: "eyJ2IjpbeyJ0IjoieCJ9XSwibyI6IlwiaWRcIiBBU0MifQ", order: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity.FindPage(nil, tt.cursor, 10, tt.order...)
+			if err == nil || !strings.Contains(err.Error(), "cursor") {
+				t.Errorf("FindPage error = %v, want cursor error", err)
+			}
+		})
+	}
+}
+
+type nullableEvent struct {
+	ID    int64          `db:"id"`
+	Title sql.NullString `db:"title"`
+}
+
+func TestEncodeCursorNullValue(t *testing.T) {
+	entity := newTestEntity[nullableEvent]("nullable_events")
+	orders := keyset(t, Order{Column: "title"})
+
+	if _, err := entity.encodeCursor(nullableEvent{ID: 1, Title: sql.NullString{String: "a", Valid: true}}, orders, false); err != nil {
+		t.Errorf("encodeCursor error = %v", err)
+	}
+	if _, err := entity.encodeCursor(nullableEvent{ID: 2}, orders, false); err == nil {
+		t.Error("encodeCursor with NULL value error = nil, want error")
+	}
+
+	// {"v":[{"t":"n"},{"t":"i","v":"2"}],"o":"\"title\" ASC,\"id\" ASC"}
+	nullCursor := "eyJ2IjpbeyJ0IjoibiJ9LHsidCI6ImkiLCJ2IjoiMiJ9XSwibyI6IlwidGl0bGVcIiBBU0MsXCJpZFwiIEFTQyJ9"
+	if _, err := entity.FindPage(nil, nullCursor, 10, Order{Column: "title"}); err == nil {
+		t.Error("FindPage with NULL cursor value error = nil, want error")
+	}
+	if _, err := entity.FindPage(nil, "", 10, Order{Column: "title", Nulls: NullsFirst}); err == nil {
+		t.Error("FindPage with Nulls error = nil, want error")
+	}
+}
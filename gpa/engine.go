@@ -1,27 +1,51 @@
 package gpa
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"sync"
+)
 
-var engine *Engine
+// engine default Engine used by From, WithTx and other package level helpers
+var (
+	engine   *Engine
+	engineMu sync.RWMutex
+)
 
 type Config struct {
 	IsLazy bool
+	// CountStrategy how FindPageWithTotal counts matched rows
+	CountStrategy CountStrategy
 }
 
+type CountStrategy int
+
+const (
+	// CountQuery counts rows with separate COUNT(*) query
+	CountQuery CountStrategy = iota
+	// CountWindow counts rows with COUNT(*) OVER() window in the same query
+	CountWindow
+)
+
 type DbProviderI interface {
 	sqlx.Ext
+	sqlx.ExtContext
 	sqlx.Preparer
+	sqlx.PreparerContext
 	Get(dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	Select(dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	PrepareNamed(query string) (*sqlx.NamedStmt, error)
+	PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error)
 }
 
 type Engine struct {
-	entityTableNameMap map[any]string
-	tableNameEntityMap map[string]any
-	db                 *sqlx.DB
-	t                  *sqlx.Tx
-	cfg                Config
+	registry *registry
+	db       *sqlx.DB
+	t        *sqlx.Tx
+	cfg      Config
 }
 
 func (e *Engine) GetInstance() DbProviderI {
@@ -32,25 +56,53 @@ func (e *Engine) GetInstance() DbProviderI {
 }
 
 func (e *Engine) SetTableName(entity any, tableName string) {
-	e.entityTableNameMap[entity] = tableName
-	e.tableNameEntityMap[tableName] = entity
+	e.registry.setTableName(entity, tableName)
 }
 
 func (e *Engine) GetTableName(entity any) (string, bool) {
-	val, ok := e.entityTableNameMap[entity]
-	return val, ok
+	return e.registry.getTableName(entity)
 }
 
 func (e *Engine) GetEntity(tableName string) (any, bool) {
-	val, ok := e.tableNameEntityMap[tableName]
-	return val, ok
+	return e.registry.getEntity(tableName)
+}
+
+// NewEngine creates Engine instance for the db and makes it default one used by From.
+func NewEngine(db *sqlx.DB, cfg Config) *Engine {
+	e := NewIndependentEngine(db, cfg)
+	SetDefaultEngine(e)
+	return e
+}
+
+// NewIndependentEngine creates Engine instance for the db without changing the default engine,
+// for additional databases used with FromEngine.
+func NewIndependentEngine(db *sqlx.DB, cfg Config) *Engine {
+	return &Engine{
+		registry: newRegistry(),
+		db:       db,
+		cfg:      cfg,
+	}
+}
+
+// SetDefaultEngine replaces engine used by From and other package level helpers.
+func SetDefaultEngine(e *Engine) {
+	engineMu.Lock()
+	defer engineMu.Unlock()
+	engine = e
+}
+
+// DefaultEngine returns engine used by From and other package level helpers.
+func DefaultEngine() *Engine {
+	engineMu.RLock()
+	defer engineMu.RUnlock()
+	return engine
 }
 
-func NewEngine(db *sqlx.DB, cfg Config) {
-	engine = &Engine{
-		entityTableNameMap: make(map[any]string, 0),
-		tableNameEntityMap: make(map[string]any, 0),
-		db:                 db,
-		cfg:                cfg,
+// defaultEngine returns the default engine, ErrNotConfigured when NewEngine or SetDefaultEngine wasn't called
+func defaultEngine() (*Engine, error) {
+	e := DefaultEngine()
+	if e == nil {
+		return nil, errors.Wrap(ErrNotConfigured, "gpa default engine isn't set")
 	}
+	return e, nil
 }
@@ -0,0 +1,115 @@
+package gpa
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+var (
+	// ErrNotFound returned when no rows were found, wraps sql.ErrNoRows
+	ErrNotFound = errors.New("gpa entity not found")
+	// ErrNotConfigured returned when entity table wasn't resolved
+	ErrNotConfigured = errors.New("gpa entity wasn't configured")
+
+	// ErrFullTable returned by bulk methods called with empty or always true filters and without AllowFullTable option
+	ErrFullTable = errors.New("gpa refuses to change the whole table")
+
+	// ErrNoTransaction returned by methods which should be called with the entity from FromTx
+	ErrNoTransaction = errors.New("gpa method requires transaction")
+
+	ErrUniqueViolation      = errors.New("gpa unique violation")
+	ErrForeignKeyViolation  = errors.New("gpa foreign key violation")
+	ErrCheckViolation       = errors.New("gpa check violation")
+	ErrSerializationFailure = errors.New("gpa serialization failure")
+)
+
+// pgErrorKinds Postgres SQLSTATE codes mapped to gpa errors
+var pgErrorKinds = map[string]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"23514": ErrCheckViolation,
+	"40001": ErrSerializationFailure,
+}
+
+// InvalidEntityError returned when entity or item passed to gpa isn't a structure or pointer to structure
+type InvalidEntityError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidEntityError) Error() string {
+	return fmt.Sprintf("gpa entity should be structure, got %v instead", e.Type)
+}
+
+// UnknownColumnError returned when column isn't defined with db tag of the entity
+type UnknownColumnError struct {
+	Entity reflect.Type
+	Column string
+}
+
+func (e *UnknownColumnError) Error() string {
+	return fmt.Sprintf("gpa entity %v has no column %q", e.Entity, e.Column)
+}
+
+// DBError classified database error.
+// Matches its Kind with errors.Is, f.e. errors.Is(err, gpa.ErrUniqueViolation),
+// original error (*pgconn.PgError or sql.ErrNoRows) is still available with errors.As and errors.Is.
+type DBError struct {
+	Kind       error
+	Code       string
+	Table      string
+	Constraint string
+	Column     string
+	Err        error
+}
+
+func (e *DBError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Err)
+}
+
+func (e *DBError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError converts sql.ErrNoRows and known Postgres errors to DBError
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &DBError{Kind: ErrNotFound, Err: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if kind, ok := pgErrorKinds[pgErr.Code]; ok {
+			return &DBError{
+				Kind:       kind,
+				Code:       pgErr.Code,
+				Table:      pgErr.TableName,
+				Constraint: pgErr.ConstraintName,
+				Column:     pgErr.ColumnName,
+				Err:        err,
+			}
+		}
+	}
+	return err
+}
+
+// checkAffected returns ErrNotFound when the query by id hasn't affected any row
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return &DBError{Kind: ErrNotFound, Err: sql.ErrNoRows}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package gpa
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	pkgerrors "github.com/pkg/errors"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	unique := &pgconn.PgError{Code: "23505", TableName: "users", ConstraintName: "users_email_key", ColumnName: "email"}
+	tests := []struct {
+		name           string
+		err            error
+		wantKind       error
+		wantCode       string
+		wantConstraint string
+		wantColumn     string
+	}{
+		{name: "unique", err: unique, wantKind: ErrUniqueViolation, wantCode: "23505", wantConstraint: "users_email_key", wantColumn: "email"},
+		{name: "wrapped unique", err: pkgerrors.Wrap(unique, "insert"), wantKind: ErrUniqueViolation, wantCode: "23505", wantConstraint: "users_email_key", wantColumn: "email"},
+		{name: "fmt wrapped unique", err: fmt.Errorf("insert: %w", unique), wantKind: ErrUniqueViolation, wantCode: "23505", wantConstraint: "users_email_key", wantColumn: "email"},
+		{name: "foreign key", err: &pgconn.PgError{Code: "23503", ConstraintName: "user_roles_role_id_fkey"}, wantKind: ErrForeignKeyViolation, wantCode: "23503", wantConstraint: "user_roles_role_id_fkey"},
+		{name: "check", err: &pgconn.PgError{Code: "23514", ConstraintName: "views_positive"}, wantKind: ErrCheckViolation, wantCode: "23514", wantConstraint: "views_positive"},
+		{name: "serialization", err: &pgconn.PgError{Code: "40001"}, wantKind: ErrSerializationFailure, wantCode: "40001"},
+		{name: "no rows", err: sql.ErrNoRows, wantKind: ErrNotFound},
+		{name: "wrapped no rows", err: pkgerrors.Wrap(sql.ErrNoRows, "get"), wantKind: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			if !errors.Is(err, tt.wantKind) {
+				t.Errorf("classifyError error = %v, want %v", err, tt.wantKind)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classifyError error = %v doesn't wrap the original error", err)
+			}
+
+			var dbErr *DBError
+			if !errors.As(err, &dbErr) {
+				t.Fatalf("classifyError error = %v, want DBError", err)
+			}
+			if dbErr.Code != tt.wantCode || dbErr.Constraint != tt.wantConstraint || dbErr.Column != tt.wantColumn {
+				t.Errorf("DBError = %+v, want code %q, constraint %q, column %q", dbErr, tt.wantCode, tt.wantConstraint, tt.wantColumn)
+			}
+		})
+	}
+
+	if err := classifyError(sql.ErrNoRows); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("classifyError(sql.ErrNoRows) = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestClassifyErrorPassThrough(t *testing.T) {
+	if err := classifyError(nil); err != nil {
+		t.Errorf("classifyError(nil) = %v, want nil", err)
+	}
+
+	other := &pgconn.PgError{Code: "42P01"}
+	var dbErr *DBError
+	if err := classifyError(other); err != other || errors.As(err, &dbErr) {
+		t.Errorf("classifyError(%v) = %v, want the same error", other, err)
+	}
+}
+
+type affectedResult int64
+
+func (r affectedResult) LastInsertId() (int64, error) { return 0, nil }
+
+func (r affectedResult) RowsAffected() (int64, error) {
+	if r < 0 {
+		return 0, errors.New("rows affected isn't supported")
+	}
+	return int64(r), nil
+}
+
+func TestCheckAffected(t *testing.T) {
+	if err := checkAffected(affectedResult(1)); err != nil {
+		t.Errorf("checkAffected(1) = %v, want nil", err)
+	}
+
+	err := checkAffected(affectedResult(0))
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("checkAffected(0) = %v, want ErrNotFound and sql.ErrNoRows", err)
+	}
+
+	if err := checkAffected(affectedResult(-1)); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("checkAffected with RowsAffected error = %v, want the error", err)
+	}
+}
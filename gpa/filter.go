@@ -0,0 +1,246 @@
+package gpa
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+var signs = map[Sign]bool{
+	Equal: true, NotEqual: true, MoreEqual: true, LessEqual: true, More: true, Less: true,
+	Like: true, ILike: true, In: true, NotIn: true, IsNull: true, IsNotNull: true, Between: true, Any: true,
+}
+
+// whereBuilder compiles filters to SQL conditions with numbered placeholders.
+// Filter field names are resolved against entity db tags and quoted, signs are checked against known ones.
+type whereBuilder struct {
+	entity        reflect.Type
+	fields        MetaDataList
+	paramsCounter int
+	values        []interface{}
+}
+
+// truth constant value of compiled condition, conditions depending on columns values are unknown
+type truth int
+
+const (
+	unknown truth = iota
+	alwaysTrue
+	alwaysFalse
+)
+
+func (t truth) and(other truth) truth {
+	switch {
+	case t == alwaysFalse || other == alwaysFalse:
+		return alwaysFalse
+	case t == alwaysTrue:
+		return other
+	case other == alwaysTrue:
+		return t
+	}
+	return unknown
+}
+
+func (t truth) or(other truth) truth {
+	switch {
+	case t == alwaysTrue || other == alwaysTrue:
+		return alwaysTrue
+	case t == alwaysFalse:
+		return other
+	case other == alwaysFalse:
+		return t
+	}
+	return unknown
+}
+
+func (t truth) not() truth {
+	switch t {
+	case alwaysTrue:
+		return alwaysFalse
+	case alwaysFalse:
+		return alwaysTrue
+	}
+	return unknown
+}
+
+// getWhereQuery builds WHERE clause for filters, placeholders are numbered starting from paramsCounter
+func (e *Entity[entityType]) getWhereQuery(filters []F, paramsCounter int) (string, []interface{}, error) {
+	where, values, _, err := e.whereQuery(filters, paramsCounter)
+	return where, values, err
+}
+
+// whereQuery works as getWhereQuery, but also returns constant value of the compiled conditions,
+// empty filters match every row
+func (e *Entity[entityType]) whereQuery(filters []F, paramsCounter int) (string, []interface{}, truth, error) {
+	if len(filters) == 0 {
+		return "", make([]interface{}, 0), alwaysTrue, nil
+	}
+
+	w, err := e.newWhereBuilder(paramsCounter)
+	if err != nil {
+		return "", nil, unknown, err
+	}
+	conditions, t, err := w.conditions(filters)
+	if err != nil {
+		return "", nil, unknown, err
+	}
+	return " WHERE " + conditions, w.values, t, nil
+}
+
+func (e *Entity[entityType]) newWhereBuilder(paramsCounter int) (*whereBuilder, error) {
+	fields, err := getReflectedData(e.entityObj, true)
+	if err != nil {
+		return nil, err
+	}
+	return &whereBuilder{
+		entity:        reflect.TypeOf(e.entityObj),
+		fields:        fields,
+		paramsCounter: paramsCounter,
+		values:        make([]interface{}, 0),
+	}, nil
+}
+
+// conditions joins filters by their Cond, Cond of the last filter is ignored.
+// Returned truth follows SQL precedence, AND is evaluated before OR.
+func (w *whereBuilder) conditions(filters []F) (string, truth, error) {
+	sql := ""
+	t, chain := alwaysFalse, alwaysTrue
+	for i, f := range filters {
+		if i > 0 {
+			cond := filters[i-1].Cond
+			switch cond {
+			case "":
+				cond = AND
+			case AND, OR:
+			default:
+				return "", unknown, errors.Errorf("gpa filter %s has unknown condition %q", filters[i-1].FieldName, cond)
+			}
+			if cond == OR {
+				t, chain = t.or(chain), alwaysTrue
+			}
+			sql += " " + string(cond) + " "
+		}
+
+		condition, conditionTruth, err := w.condition(f)
+		if err != nil {
+			return "", unknown, err
+		}
+		chain = chain.and(conditionTruth)
+		sql += condition
+	}
+	return sql, t.or(chain), nil
+}
+
+func (w *whereBuilder) condition(f F) (string, truth, error) {
+	if f.groupCond == "" {
+		return w.comparison(f)
+	}
+
+	sql := ""
+	t := unknown
+	if len(f.group) == 0 {
+		// empty AND matches everything, empty OR matches nothing
+		sql, t = "TRUE", alwaysTrue
+		if f.groupCond == OR {
+			sql, t = "FALSE", alwaysFalse
+		}
+	} else {
+		parts := make([]string, 0)
+		for i, g := range f.group {
+			condition, conditionTruth, err := w.condition(g)
+			if err != nil {
+				return "", unknown, err
+			}
+			switch {
+			case i == 0:
+				t = conditionTruth
+			case f.groupCond == OR:
+				t = t.or(conditionTruth)
+			default:
+				t = t.and(conditionTruth)
+			}
+			parts = append(parts, condition)
+		}
+		sql = strings.Join(parts, " "+string(f.groupCond)+" ")
+	}
+
+	if f.not {
+		return "NOT (" + sql + ")", t.not(), nil
+	}
+	return "(" + sql + ")", t, nil
+}
+
+func (w *whereBuilder) comparison(f F) (string, truth, error) {
+	column, err := w.column(f.FieldName)
+	if err != nil {
+		return "", unknown, err
+	}
+	if !signs[f.Sign] {
+		return "", unknown, errors.Errorf("gpa filter %s has unknown sign %q", f.FieldName, f.Sign)
+	}
+
+	switch f.Sign {
+	case IsNull, IsNotNull:
+		return fmt.Sprintf("%s %s", column, f.Sign), unknown, nil
+	case In, NotIn:
+		items, err := sliceValues(f)
+		if err != nil {
+			return "", unknown, err
+		}
+		if len(items) == 0 {
+			// IN () isn't valid SQL, empty IN matches nothing and empty NOT IN matches everything
+			if f.Sign == In {
+				return "FALSE", alwaysFalse, nil
+			}
+			return "TRUE", alwaysTrue, nil
+		}
+
+		placeholders := make([]string, 0)
+		for _, item := range items {
+			placeholders = append(placeholders, w.placeholder(item))
+		}
+		return fmt.Sprintf("%s %s (%s)", column, f.Sign, strings.Join(placeholders, ", ")), unknown, nil
+	case Between:
+		items, err := sliceValues(f)
+		if err != nil {
+			return "", unknown, err
+		}
+		if len(items) != 2 {
+			return "", unknown, errors.Errorf("gpa filter %s %s expects two values, got %d", f.FieldName, f.Sign, len(items))
+		}
+		return fmt.Sprintf("%s %s %s AND %s", column, f.Sign, w.placeholder(items[0]), w.placeholder(items[1])), unknown, nil
+	case Any:
+		return fmt.Sprintf("%s %s(%s)", column, f.Sign, w.placeholder(f.Value)), unknown, nil
+	}
+	return fmt.Sprintf("%s %s %s", column, f.Sign, w.placeholder(f.Value)), unknown, nil
+}
+
+// column returns quoted column name, UnknownColumnError when entity has no such db tag
+func (w *whereBuilder) column(name string) (string, error) {
+	if w.fields.GetDataByDBTag(name).FieldDb == "" {
+		return "", &UnknownColumnError{Entity: w.entity, Column: name}
+	}
+	return quoteIdent(name), nil
+}
+
+// placeholder adds value to the query parameters and returns its placeholder
+func (w *whereBuilder) placeholder(value interface{}) string {
+	w.values = append(w.values, value)
+	w.paramsCounter++
+	return fmt.Sprintf("$%d", w.paramsCounter-1)
+}
+
+// sliceValues returns elements of the filter slice or array value
+func sliceValues(f F) ([]interface{}, error) {
+	v := reflect.ValueOf(f.Value)
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, errors.Errorf("gpa filter %s %s expects slice value, got %T", f.FieldName, f.Sign, f.Value)
+	}
+
+	items := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items = append(items, v.Index(i).Interface())
+	}
+	return items, nil
+}
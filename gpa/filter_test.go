@@ -0,0 +1,148 @@
+package gpa
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetWhereQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		filters       []F
+		paramsCounter int
+		wantQuery     string
+		wantValues    []interface{}
+		wantColumn    string
+		wantErr       bool
+	}{
+		{
+			name:       "comparison",
+			filters:    []F{{FieldName: "title", Sign: Equal, Value: "id; DROP TABLE x--"}},
+			wantQuery:  ` WHERE "title" = $1`,
+			wantValues: []interface{}{"id; DROP TABLE x--"},
+		},
+		{
+			name:          "params counter",
+			filters:       []F{{FieldName: "views", Sign: More, Value: 1, Cond: OR}, {FieldName: "title", Sign: In, Value: []string{"a", "b"}}},
+			paramsCounter: 3,
+			wantQuery:     ` WHERE "views" > $3 OR "title" IN ($4, $5)`,
+			wantValues:    []interface{}{1, "a", "b"},
+		},
+		{
+			name:       "injection in field name",
+			filters:    []F{{FieldName: "id; DROP TABLE x--", Sign: Equal, Value: 1}},
+			wantColumn: "id; DROP TABLE x--",
+		},
+		{
+			name:       "quote in field name",
+			filters:    []F{{FieldName: `title" = '' OR "id`, Sign: Equal, Value: 1}},
+			wantColumn: `title" = '' OR "id`,
+		},
+		{
+			name:       "injection in nested field name",
+			filters:    []F{Not(Or(F{FieldName: "views", Sign: More, Value: 1}, F{FieldName: "1=1) --", Sign: Equal, Value: 1}))},
+			wantColumn: "1=1) --",
+		},
+		{
+			name:    "injection in sign",
+			filters: []F{{FieldName: "id", Sign: "= 1 OR 1=1 --", Value: 1}},
+			wantErr: true,
+		},
+		{
+			name:    "injection in condition",
+			filters: []F{{FieldName: "id", Sign: Equal, Value: 1, Cond: "OR 1=1 OR"}, {FieldName: "id", Sign: Equal, Value: 2}},
+			wantErr: true,
+		},
+		{
+			name:    "in without slice",
+			filters: []F{{FieldName: "id", Sign: In, Value: "1, 2) OR (1=1"}},
+			wantErr: true,
+		},
+		{
+			name:    "between with one value",
+			filters: []F{{FieldName: "views", Sign: Between, Value: []int{1}}},
+			wantErr: true,
+		},
+	}
+
+	entity := newTestEntity[bulkDocument]("documents")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paramsCounter := tt.paramsCounter
+			if paramsCounter == 0 {
+				paramsCounter = 1
+			}
+			query, values, err := entity.getWhereQuery(tt.filters, paramsCounter)
+
+			var unknownColumn *UnknownColumnError
+			switch {
+			case tt.wantColumn != "":
+				if !errors.As(err, &unknownColumn) || unknownColumn.Column != tt.wantColumn {
+					t.Errorf("getWhereQuery error = %v, want UnknownColumnError of %q", err, tt.wantColumn)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &unknownColumn) {
+					t.Errorf("getWhereQuery error = %v, want filter error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("getWhereQuery error = %v", err)
+				}
+				if query != tt.wantQuery {
+					t.Errorf("getWhereQuery query = %s, want %s", query, tt.wantQuery)
+				}
+				if !reflect.DeepEqual(values, tt.wantValues) {
+					t.Errorf("getWhereQuery values = %#v, want %#v", values, tt.wantValues)
+				}
+			}
+		})
+	}
+}
+
+func TestGetOrderQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		orders     []Order
+		wantQuery  string
+		wantColumn string
+		wantErr    bool
+	}{
+		{name: "empty", orders: nil, wantQuery: ""},
+		{
+			name:      "directions and nulls",
+			orders:    []Order{{Column: "title", Direction: Desc, Nulls: NullsLast}, {Column: "id"}},
+			wantQuery: ` ORDER BY "title" DESC NULLS LAST, "id"`,
+		},
+		{name: "injection in column", orders: []Order{{Column: "id; DROP TABLE x--"}}, wantColumn: "id; DROP TABLE x--"},
+		{name: "quote in column", orders: []Order{{Column: `id", (SELECT 1) --`}}, wantColumn: `id", (SELECT 1) --`},
+		{name: "injection in direction", orders: []Order{{Column: "id", Direction: "ASC; DROP TABLE x--"}}, wantErr: true},
+		{name: "injection in nulls", orders: []Order{{Column: "id", Nulls: "NULLS FIRST, 1"}}, wantErr: true},
+	}
+
+	entity := newTestEntity[bulkDocument]("documents")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := entity.getOrderQuery(tt.orders)
+
+			var unknownColumn *UnknownColumnError
+			switch {
+			case tt.wantColumn != "":
+				if !errors.As(err, &unknownColumn) || unknownColumn.Column != tt.wantColumn {
+					t.Errorf("getOrderQuery error = %v, want UnknownColumnError of %q", err, tt.wantColumn)
+				}
+			case tt.wantErr:
+				if err == nil {
+					t.Error("getOrderQuery error = nil, want order error")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("getOrderQuery error = %v", err)
+				}
+				if query != tt.wantQuery {
+					t.Errorf("getOrderQuery query = %s, want %s", query, tt.wantQuery)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package gpa
+
+import "strings"
+
+// quoteIdent quotes identifier, so table or column name can't break out of the query
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteIdents quotes every identifier of names
+func quoteIdents(names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteIdent(name))
+	}
+	return quoted
+}
+
+// quoteTable quotes table name, schema qualified name is quoted by parts.
+// Name is lowercased as Postgres folds unquoted names, so configured "UserRoles" is still userroles table.
+func quoteTable(name string) string {
+	return strings.Join(quoteIdents(strings.Split(strings.ToLower(name), ".")), ".")
+}
@@ -0,0 +1,51 @@
+package gpa
+
+import "testing"
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "title", want: `"title"`},
+		{name: "Title", want: `"Title"`},
+		{name: `ti"tle`, want: `"ti""tle"`},
+		{name: `"; DROP TABLE x--`, want: `"""; DROP TABLE x--"`},
+		{name: "id; DROP TABLE x--", want: `"id; DROP TABLE x--"`},
+		{name: "", want: `""`},
+	}
+	for _, tt := range tests {
+		if got := quoteIdent(tt.name); got != tt.want {
+			t.Errorf("quoteIdent(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteTable(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "documents", want: `"documents"`},
+		{name: "public.documents", want: `"public"."documents"`},
+		{name: `public".documents`, want: `"public"""."documents"`},
+		{name: `docs"; DROP TABLE x--`, want: `"docs""; drop table x--"`},
+		{name: "UserRoles", want: `"userroles"`},
+		{name: "Public.UserRoles", want: `"public"."userroles"`},
+	}
+	for _, tt := range tests {
+		if got := quoteTable(tt.name); got != tt.want {
+			t.Errorf("quoteTable(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEntityTableNameFoldsCase(t *testing.T) {
+	got, err := newTestEntity[metaRole]("UserRoles").tableName()
+	if err != nil {
+		t.Fatalf("tableName error = %v", err)
+	}
+	if want := `"userroles"`; got != want {
+		t.Errorf("tableName = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package gpa
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// defaultBatchSize rows fetched by Iterate at once
+const defaultBatchSize = 1000
+
+// cursorCounter makes server-side cursor names unique
+var cursorCounter uint64
+
+// Iterate passes rows matched by filters to fn one by one without loading all of them to memory,
+// iteration stops on the first fn error which is returned. Lazy entities are fetched as FindAll does.
+// Rows are fetched in batches and no rows are held open while fn is called, so fn could run queries,
+// f.e. with the entity from FromTx. Outside a transaction batches are fetched as FindPage ordered by id,
+// so entity should have id column and rows changed concurrently could be skipped or seen changed.
+// Inside a transaction rows are fetched with IterateCursor.
+func (e *Entity[entityType]) Iterate(ctx context.Context, filters []F, fn func(entityType) error) error {
+	if e.engine.t != nil {
+		return e.IterateCursor(ctx, filters, defaultBatchSize, fn)
+	}
+
+	pageCursor := ""
+	for {
+		page, err := e.FindPageContext(ctx, filters, pageCursor, defaultBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, entity := range page.Items {
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+		if page.Next == "" {
+			return nil
+		}
+		pageCursor = page.Next
+	}
+}
+
+// IterateCursor works as Iterate, but fetches rows in batches of batchSize with a server-side cursor,
+// every batch is read before fn is called. Should be called with the entity from FromTx, returns ErrNoTransaction otherwise.
+func (e *Entity[entityType]) IterateCursor(ctx context.Context, filters []F, batchSize int, fn func(entityType) error) (err error) {
+	query, values, err := e.iterateQuery(filters)
+	if err != nil {
+		return err
+	}
+	if e.engine.t == nil {
+		return ErrNoTransaction
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	cursorName := fmt.Sprintf("gpa_cursor_%d", atomic.AddUint64(&cursorCounter, 1))
+	if _, err := e.engine.GetInstance().ExecContext(ctx, "DECLARE "+cursorName+" NO SCROLL CURSOR FOR "+query, values...); err != nil {
+		return classifyError(err)
+	}
+	defer func() {
+		// cursor is open until the transaction ends otherwise, the first error is kept
+		if _, closeErr := e.engine.GetInstance().ExecContext(ctx, "CLOSE "+cursorName); err == nil {
+			err = classifyError(closeErr)
+		}
+	}()
+
+	fetch := "FETCH FORWARD " + strconv.Itoa(batchSize) + " FROM " + cursorName
+	for {
+		batch := make([]entityType, 0, batchSize)
+		if err := e.engine.GetInstance().SelectContext(ctx, &batch, fetch); err != nil {
+			return classifyError(err)
+		}
+
+		for _, entity := range batch {
+			entity, err := e.withsLazy(ctx, entity)
+			if err != nil {
+				return err
+			}
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (e *Entity[entityType]) iterateQuery(filters []F) (string, []interface{}, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return "", nil, err
+	}
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return "", nil, err
+	}
+	return "SELECT * FROM " + tableName + whereElements, values, nil
+}
@@ -1,11 +1,12 @@
 package gpa
 
 import (
+	"context"
 	"fmt"
 	"github.com/pkg/errors"
-	"log"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 type MetaLazyEntity struct {
@@ -16,12 +17,13 @@ type MetaLazyEntity struct {
 	FetchBy  string
 }
 
-func getLazyEntitiesMetaData(item interface{}) []MetaLazyEntity {
-	t := reflect.TypeOf(item)
-	if kind := t.Kind(); kind != reflect.Struct {
-		log.Fatalf("should be structure, got %v instead.", kind)
+func getLazyEntitiesMetaData(item interface{}) ([]MetaLazyEntity, error) {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil, &InvalidEntityError{Type: reflect.TypeOf(item)}
 	}
 
+	t := v.Type()
 	var lazy []MetaLazyEntity
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
@@ -35,18 +37,106 @@ func getLazyEntitiesMetaData(item interface{}) []MetaLazyEntity {
 			})
 		}
 	}
-	return lazy
+	return lazy, nil
 }
 
-func (e *Entity[entityType]) getPagQuery(p *Pagination) string {
-	var pagQuery = ""
-	if p != nil && p.Limit != 0 {
+// getPagQuery builds ORDER BY and pagination clauses, paginated rows are ordered by id when order isn't set
+func (e *Entity[entityType]) getPagQuery(p *Pagination) (string, error) {
+	if p == nil {
+		return "", nil
+	}
+
+	orders := p.Order
+	if len(orders) == 0 && (p.Limit != 0 || p.Offset != 0) {
+		orders = e.defaultOrder()
+	}
+	pagQuery, err := e.getOrderQuery(orders)
+	if err != nil {
+		return "", err
+	}
+
+	if p.Limit != 0 {
 		pagQuery += " LIMIT " + strconv.FormatInt(p.Limit, 10)
 	}
-	if p != nil && p.Offset != 0 {
+	if p.Offset != 0 {
 		pagQuery += " OFFSET " + strconv.FormatInt(p.Offset, 10)
 	}
-	return pagQuery
+	return pagQuery, nil
+}
+
+// getLockQuery builds row locking clause, locks can be used only inside a transaction
+func (e *Entity[entityType]) getLockQuery(locks []Lock) (string, error) {
+	if len(locks) == 0 {
+		return "", nil
+	}
+	if len(locks) > 1 {
+		return "", errors.New("gpa only one lock is allowed")
+	}
+	if e.engine.t == nil {
+		return "", errors.Wrap(ErrNoTransaction, "gpa row lock")
+	}
+
+	lock := locks[0]
+	switch lock.Strength {
+	case ForUpdate, ForNoKeyUpdate, ForShare, ForKeyShare:
+	default:
+		return "", errors.Errorf("gpa unknown lock strength %q", lock.Strength)
+	}
+	lockQuery := " " + string(lock.Strength)
+	switch lock.Wait {
+	case "":
+	case NoWait, SkipLocked:
+		lockQuery += " " + string(lock.Wait)
+	default:
+		return "", errors.Errorf("gpa unknown lock wait %q", lock.Wait)
+	}
+	return lockQuery, nil
+}
+
+// defaultOrder orders by primary key, so paginated results are stable
+func (e *Entity[entityType]) defaultOrder() []Order {
+	fields, err := getReflectedData(e.entityObj, true)
+	if err != nil || fields.GetDataByDBTag("id").FieldDb == "" {
+		return nil
+	}
+	return []Order{{Column: "id", Direction: Asc}}
+}
+
+// getOrderQuery builds ORDER BY clause, columns are validated against entity db tags
+func (e *Entity[entityType]) getOrderQuery(orders []Order) (string, error) {
+	if len(orders) == 0 {
+		return "", nil
+	}
+
+	fields, err := getReflectedData(e.entityObj, true)
+	if err != nil {
+		return "", err
+	}
+
+	items := make([]string, 0)
+	for _, o := range orders {
+		if fields.GetDataByDBTag(o.Column).FieldDb == "" {
+			return "", &UnknownColumnError{Entity: reflect.TypeOf(e.entityObj), Column: o.Column}
+		}
+
+		item := quoteIdent(o.Column)
+		switch o.Direction {
+		case "":
+		case Asc, Desc:
+			item += " " + string(o.Direction)
+		default:
+			return "", errors.Errorf("gpa unknown order direction %q", o.Direction)
+		}
+		switch o.Nulls {
+		case "":
+		case NullsFirst, NullsLast:
+			item += " " + string(o.Nulls)
+		default:
+			return "", errors.Errorf("gpa unknown order nulls %q", o.Nulls)
+		}
+		items = append(items, item)
+	}
+	return " ORDER BY " + strings.Join(items, ", "), nil
 }
 
 // EntityMetadataInfo MetaData info structure for saving information about field
@@ -128,12 +218,13 @@ func (m MetaDataList) GetFieldEntity() []interface{} {
 	return arr
 }
 
-func getReflectedData(item interface{}, withId bool) MetaDataList {
-	t := reflect.TypeOf(item)
-	if kind := t.Kind(); kind != reflect.Struct {
-		log.Fatalf("should be structure, got %v instead.", kind)
+func getReflectedData(item interface{}, withId bool) (MetaDataList, error) {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil, &InvalidEntityError{Type: reflect.TypeOf(item)}
 	}
 
+	t := v.Type()
 	metaDataFields := make(MetaDataList, 0)
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
@@ -155,25 +246,28 @@ func getReflectedData(item interface{}, withId bool) MetaDataList {
 			}
 
 			if f.Type.Kind() == reflect.Struct {
-				fieldValue := reflect.ValueOf(item).Field(i).Interface()
-				reflMetaData := getReflectedData(fieldValue, withId)
+				fieldValue := v.Field(i).Interface()
+				reflMetaData, err := getReflectedData(fieldValue, withId)
+				if err != nil {
+					return nil, err
+				}
 				metaData.FieldEntity = reflMetaData
 			}
 			metaDataFields = append(metaDataFields, metaData)
 		}
 	}
 
-	return metaDataFields
+	return metaDataFields, nil
 }
 
-func (e *Entity[entityType]) withLazies(entities []entityType) ([]entityType, error) {
-	if !engine.cfg.IsLazy {
+func (e *Entity[entityType]) withLazies(ctx context.Context, entities []entityType) ([]entityType, error) {
+	if !e.engine.cfg.IsLazy {
 		return entities, nil
 	}
 
 	lzs := make([]entityType, 0)
 	for i := 0; i < len(entities); i++ {
-		lz, err := e.withsLazy(entities[i])
+		lz, err := e.withsLazy(ctx, entities[i])
 		if err != nil {
 			return nil, errors.Wrap(err, "error fetching lazy entity")
 		}
@@ -182,37 +276,47 @@ func (e *Entity[entityType]) withLazies(entities []entityType) ([]entityType, er
 	return lzs, nil
 }
 
-func (e *Entity[entityType]) withsLazy(entity entityType) (entityType, error) {
-	if !engine.cfg.IsLazy {
+func (e *Entity[entityType]) withsLazy(ctx context.Context, entity entityType) (entityType, error) {
+	if !e.engine.cfg.IsLazy {
 		return entity, nil
 	}
 
-	lazyEntities := getLazyEntitiesMetaData(entity)
+	lazyEntities, err := getLazyEntitiesMetaData(entity)
+	if err != nil {
+		return entity, err
+	}
 	for i := 0; i < len(lazyEntities); i++ {
 		lazyEntityMeta := lazyEntities[i]
-		lazySingleEntityType := lazyEntityMeta.Type
+		if !isLazyType(lazyEntityMeta.Type) {
+			return entity, errors.Errorf("gpa lazy field of %T should be *T or *[]T of struct, got %s", entity, lazyEntityMeta.Type)
+		}
 
-		generalLazy, ok := engine.GetEntity(lazyEntityMeta.Join)
+		generalLazy, ok := e.engine.GetEntity(lazyEntityMeta.Join)
 		if !ok {
 			return entity, errors.New("lazy type [" + lazyEntityMeta.Join + "] can't be found or wasn't initialized before")
 		}
 
 		lazyEntity := generalLazy
-		if lazyEntityMeta.Type.Elem().Kind() == reflect.Slice {
-			lazySingleEntityType = lazyEntityMeta.Type.Elem().Elem()
-			lazyEntity = reflect.New(lazySingleEntityType).Elem().Interface()
+		single := lazyEntityMeta.Type.Elem().Kind() != reflect.Slice
+		if !single {
+			lazyEntity = reflect.New(lazyEntityMeta.Type.Elem().Elem()).Elem().Interface()
+		} else if reflect.TypeOf(generalLazy) != lazyEntityMeta.Type.Elem() {
+			return entity, errors.Errorf("gpa lazy field of %T has type %s, but joined entity is %T", entity, lazyEntityMeta.Type, generalLazy)
 		}
 
-		lazyTable, ok := engine.GetTableName(lazyEntity)
+		lazyTable, ok := e.engine.GetTableName(lazyEntity)
 		if !ok {
 			return entity, errors.New("lazy type can't be found or wasn't initialized before")
 		}
-		currentTable, ok := engine.GetTableName(e.entityObj)
+		currentTable, ok := e.engine.GetTableName(e.entityObj)
 		if !ok {
 			return entity, errors.New("current table can't be found or wasn't initialized before")
 		}
 
-		lmtd := getReflectedData(generalLazy, true)
+		lmtd, err := getReflectedData(generalLazy, true)
+		if err != nil {
+			return entity, err
+		}
 		joinedTableId := lmtd.GetMappedByMetaJoin(lazyTable)
 		joinedTableCurrentId := lmtd.GetMappedByMetaJoin(currentTable)
 
@@ -226,21 +330,28 @@ func (e *Entity[entityType]) withsLazy(entity entityType) (entityType, error) {
 			}
 		}
 
-		inheretedWhere := " " + lazyEntityMeta.MappedBy + " "
+		inheretedWhere := " " + quoteIdent(lazyEntityMeta.MappedBy) + " "
 		if lazyEntityMeta.Join != lazyTable {
-			whereId := " WHERE " + lazyEntityMeta.MappedBy + fmt.Sprintf(" = $1")
-			inheretedWhere = " SELECT " + lazyEntityMeta.FetchBy + " FROM " + lazyEntityMeta.Join + whereId
+			whereId := " WHERE " + quoteIdent(lazyEntityMeta.MappedBy) + " = $1"
+			inheretedWhere = " SELECT " + quoteIdent(lazyEntityMeta.FetchBy) + " FROM " + quoteTable(lazyEntityMeta.Join) + whereId
 		}
 
-		query := fmt.Sprintf("SELECT * FROM "+lazyTable+" WHERE %s IN (%s)", joinedTableId, inheretedWhere)
+		query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN (%s)", quoteTable(lazyTable), quoteIdent(joinedTableId), inheretedWhere)
 
 		ptr := reflect.New(reflect.SliceOf(reflect.TypeOf(lazyEntity)))
 		iface := ptr.Interface()
-		if err := engine.GetInstance().Select(iface, query, joinValue); err != nil {
-			return entity, err
+		if err := e.engine.GetInstance().SelectContext(ctx, iface, query, joinValue); err != nil {
+			return entity, classifyError(err)
 		}
 
 		val := reflect.ValueOf(iface)
+		if single {
+			// pointer to the first fetched entity, nil when there are no rows
+			val = reflect.Zero(lazyEntityMeta.Type)
+			if items := reflect.ValueOf(iface).Elem(); items.Len() > 0 {
+				val = items.Index(0).Addr()
+			}
+		}
 		reflect.Indirect(reflect.ValueOf(&entity)).Field(lazyEntityMeta.Idx).Set(val)
 	}
 	return entity, nil
@@ -0,0 +1,178 @@
+package gpa
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"testing"
+)
+
+type metaRole struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type metaUser struct {
+	ID    int64       `db:"id"`
+	Name  string      `db:"name"`
+	Roles *[]metaRole `join:"user_roles" fetchBy:"role_id" mappedBy:"user_id" fetch:"lazy"`
+}
+
+func TestGetReflectedDataEntityTypes(t *testing.T) {
+	var nilUser *metaUser
+	tests := []struct {
+		name    string
+		item    interface{}
+		invalid bool
+	}{
+		{name: "struct", item: metaUser{}},
+		{name: "pointer", item: &metaUser{}},
+		{name: "slice", item: []metaUser{}, invalid: true},
+		{name: "int", item: 1, invalid: true},
+		{name: "nil interface", item: nil, invalid: true},
+		{name: "nil pointer", item: nilUser, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var invalid *InvalidEntityError
+
+			fields, err := getReflectedData(tt.item, true)
+			if tt.invalid {
+				if !errors.As(err, &invalid) {
+					t.Errorf("getReflectedData error = %v, want InvalidEntityError", err)
+				}
+			} else if err != nil || len(fields) != 2 {
+				t.Errorf("getReflectedData = %v, %v, want id and name fields", fields, err)
+			}
+
+			lazy, err := getLazyEntitiesMetaData(tt.item)
+			if tt.invalid {
+				if !errors.As(err, &invalid) {
+					t.Errorf("getLazyEntitiesMetaData error = %v, want InvalidEntityError", err)
+				}
+			} else if err != nil || len(lazy) != 1 || lazy[0].Join != "user_roles" {
+				t.Errorf("getLazyEntitiesMetaData = %v, %v, want Roles lazy field", lazy, err)
+			}
+		})
+	}
+}
+
+func TestGetPagQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *Pagination
+		want string
+	}{
+		{name: "nil", p: nil, want: ""},
+		{name: "empty", p: &Pagination{}, want: ""},
+		{name: "limit orders by id", p: &Pagination{Limit: 10}, want: ` ORDER BY "id" ASC LIMIT 10`},
+		{name: "offset orders by id", p: &Pagination{Offset: 5}, want: ` ORDER BY "id" ASC OFFSET 5`},
+		{
+			name: "explicit order",
+			p:    &Pagination{Limit: 10, Offset: 20, Order: []Order{{Column: "name", Direction: Desc}}},
+			want: ` ORDER BY "name" DESC LIMIT 10 OFFSET 20`,
+		},
+		{name: "order without pagination", p: &Pagination{Order: []Order{{Column: "name"}}}, want: ` ORDER BY "name"`},
+	}
+
+	entity := newTestEntity[metaRole]("roles")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entity.getPagQuery(tt.p)
+			if err != nil {
+				t.Fatalf("getPagQuery error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getPagQuery = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPagQueryWithoutID(t *testing.T) {
+	type tag struct {
+		Name string `db:"name"`
+	}
+	got, err := newTestEntity[tag]("tags").getPagQuery(&Pagination{Limit: 10})
+	if err != nil {
+		t.Fatalf("getPagQuery error = %v", err)
+	}
+	if want := " LIMIT 10"; got != want {
+		t.Errorf("getPagQuery = %s, want %s", got, want)
+	}
+}
+
+func TestGetLockQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		locks   []Lock
+		want    string
+		wantErr bool
+	}{
+		{name: "without lock", locks: nil, want: ""},
+		{name: "for update", locks: []Lock{{Strength: ForUpdate}}, want: " FOR UPDATE"},
+		{name: "for no key update nowait", locks: []Lock{{Strength: ForNoKeyUpdate, Wait: NoWait}}, want: " FOR NO KEY UPDATE NOWAIT"},
+		{name: "for share skip locked", locks: []Lock{{Strength: ForShare, Wait: SkipLocked}}, want: " FOR SHARE SKIP LOCKED"},
+		{name: "for key share", locks: []Lock{{Strength: ForKeyShare}}, want: " FOR KEY SHARE"},
+		{name: "empty strength", locks: []Lock{{Wait: NoWait}}, wantErr: true},
+		{name: "injection in strength", locks: []Lock{{Strength: "FOR UPDATE; DROP TABLE x--"}}, wantErr: true},
+		{name: "injection in wait", locks: []Lock{{Strength: ForUpdate, Wait: "NOWAIT; DROP TABLE x--"}}, wantErr: true},
+		{name: "several locks", locks: []Lock{{Strength: ForUpdate}, {Strength: ForShare}}, wantErr: true},
+	}
+
+	entity := newTestEntity[metaRole]("roles")
+	entity.engine.t = &sqlx.Tx{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entity.getLockQuery(tt.locks)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getLockQuery error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrNoTransaction) {
+				t.Errorf("getLockQuery error = %v inside transaction", err)
+			}
+			if got != tt.want {
+				t.Errorf("getLockQuery = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockOutsideTransaction(t *testing.T) {
+	entity := newTestEntity[metaRole]("roles")
+	lock := Lock{Strength: ForUpdate, Wait: SkipLocked}
+	calls := map[string]func() error{
+		"getLockQuery": func() error {
+			_, err := entity.getLockQuery([]Lock{lock})
+			return err
+		},
+		"FindByID": func() error {
+			_, err := entity.FindByID(1, lock)
+			return err
+		},
+		"FindBy": func() error {
+			_, err := entity.FindBy(nil, &Pagination{Limit: 1}, lock)
+			return err
+		},
+		"FindOneBy": func() error {
+			_, err := entity.FindOneBy(nil, nil, lock)
+			return err
+		},
+		"FindAll": func() error {
+			_, err := entity.FindAll(nil, lock)
+			return err
+		},
+		"QueryBuilder": func() error {
+			_, err := entity.Where("name", Equal, "admin").Lock(lock).First()
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, ErrNoTransaction) {
+				t.Errorf("%s error = %v, want ErrNoTransaction", name, err)
+			}
+		})
+	}
+}
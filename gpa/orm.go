@@ -1,17 +1,47 @@
 package gpa
 
+import "github.com/pkg/errors"
+
 type GPAEntity interface {
 	GPAConfigure(e *Engine)
 }
 
+// From returns entity bound to the default engine, its methods return ErrNotConfigured when there is no default engine.
 func From[entityType any]() *Entity[entityType] {
+	return FromEngine[entityType](DefaultEngine())
+}
+
+// FromEngine returns entity bound to the engine e, for working with several databases at once.
+func FromEngine[entityType any](e *Engine) *Entity[entityType] {
+	return fromEngine[entityType](e)
+}
+
+// FromTx works as From, but every query of the returned entity is executed
+// inside the transaction started by WithTx. Entity table is initialized outside the transaction.
+func FromTx[entityType any](tx *Tx) *Entity[entityType] {
+	entity := fromEngine[entityType](tx.parent)
+	entity.engine = tx.engine
+	return entity
+}
+
+func fromEngine[entityType any](e *Engine) *Entity[entityType] {
 	entityObject := *new(entityType)
-	_, ok := engine.entityTableNameMap[entityObject]
-	if !ok {
-		initTable(entityObject, entityObject)
+	if e == nil {
+		// engine without database, so entity methods return the error instead of panic
+		return &Entity[entityType]{
+			entityObj: entityObject,
+			engine:    &Engine{registry: newRegistry()},
+			err:       errors.Wrap(ErrNotConfigured, "gpa engine isn't set"),
+		}
 	}
 
+	err := e.registry.initOnce(entityObject, func() error {
+		return e.initTable(entityObject, registerOptions{})
+	})
+
 	return &Entity[entityType]{
 		entityObj: entityObject,
+		engine:    e,
+		err:       err,
 	}
 }
@@ -0,0 +1,94 @@
+package gpa
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFromPointerEntity(t *testing.T) {
+	prev := DefaultEngine()
+	SetDefaultEngine(NewIndependentEngine(nil, Config{}))
+	defer SetDefaultEngine(prev)
+
+	entity := From[*metaUser]()
+	var invalid *InvalidEntityError
+	if !errors.As(entity.err, &invalid) {
+		t.Fatalf("entity error = %v, want InvalidEntityError", entity.err)
+	}
+	if _, err := entity.FindBy(nil, nil); !errors.As(err, &invalid) {
+		t.Errorf("FindBy error = %v, want InvalidEntityError", err)
+	}
+	if err := entity.Delete(1); !errors.As(err, &invalid) {
+		t.Errorf("Delete error = %v, want InvalidEntityError", err)
+	}
+}
+
+func TestWithoutDefaultEngine(t *testing.T) {
+	prev := DefaultEngine()
+	SetDefaultEngine(nil)
+	defer SetDefaultEngine(prev)
+
+	ctx := context.Background()
+	entity := From[metaRole]()
+	calls := map[string]func() error{
+		"WithTx": func() error {
+			return WithTx(ctx, func(tx *Tx) error { return nil })
+		},
+		"Query": func() error {
+			_, err := Query[metaRole](ctx, "SELECT 1")
+			return err
+		},
+		"QueryOne": func() error {
+			_, err := QueryOne[int64](ctx, "SELECT 1")
+			return err
+		},
+		"Exec": func() error {
+			_, err := Exec(ctx, "SELECT 1")
+			return err
+		},
+		"FindByID": func() error {
+			_, err := entity.FindByID(1)
+			return err
+		},
+		"FindAll": func() error {
+			_, err := entity.FindAll(nil)
+			return err
+		},
+		"FindPage": func() error {
+			_, err := entity.FindPage(nil, "", 10)
+			return err
+		},
+		"Insert": func() error {
+			return entity.Insert(metaRole{Name: "admin"})
+		},
+		"Patch": func() error {
+			return entity.Patch(1, map[string]any{"name": "admin"})
+		},
+		"DeleteBy": func() error {
+			_, err := entity.DeleteBy(nil, AllowFullTable())
+			return err
+		},
+		"Upsert": func() error {
+			return entity.Upsert(&metaRole{Name: "admin"}, OnConflict{Columns: []string{"name"}, DoNothing: true})
+		},
+		"Sum": func() error {
+			_, err := Sum[int64](entity, "id", nil)
+			return err
+		},
+		"Iterate": func() error {
+			return entity.Iterate(ctx, nil, func(metaRole) error { return nil })
+		},
+		"IterateCursor": func() error {
+			return entity.IterateCursor(ctx, nil, 10, func(metaRole) error { return nil })
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, ErrNotConfigured) {
+				t.Errorf("%s error = %v, want ErrNotConfigured", name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package gpa
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx/reflectx"
+	"reflect"
+)
+
+// totalColumn window count column added by CountWindow strategy
+const totalColumn = "gpa_total"
+
+// Page page of rows with total count of rows matched by filters
+type Page[entityType any] struct {
+	Items   []entityType
+	Total   int64
+	Limit   int64
+	Offset  int64
+	HasNext bool
+}
+
+// FindPageWithTotal returns page of rows matched by filters as FindBy does, together with their total count.
+// Total is counted as configured by Config.CountStrategy, rows aren't locked.
+func (e *Entity[entityType]) FindPageWithTotal(filters []F, p *Pagination) (Page[entityType], error) {
+	return e.FindPageWithTotalContext(context.Background(), filters, p)
+}
+
+func (e *Entity[entityType]) FindPageWithTotalContext(ctx context.Context, filters []F, p *Pagination) (Page[entityType], error) {
+	if p == nil {
+		p = &Pagination{}
+	}
+	page := Page[entityType]{Items: make([]entityType, 0), Limit: p.Limit, Offset: p.Offset}
+
+	tableName, err := e.tableName()
+	if err != nil {
+		return page, err
+	}
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return page, err
+	}
+	pagQuery, err := e.getPagQuery(p)
+	if err != nil {
+		return page, err
+	}
+
+	countQuery := "SELECT COUNT(*) FROM " + tableName + whereElements
+	if e.engine.cfg.CountStrategy == CountWindow {
+		query := "SELECT *, COUNT(*) OVER() AS " + totalColumn + " FROM " + tableName + whereElements + pagQuery
+		if page.Items, page.Total, err = e.selectWithTotal(ctx, query, values); err != nil {
+			return page, err
+		}
+		// window isn't calculated when offset is out of rows
+		if len(page.Items) == 0 && p.Offset > 0 {
+			if err := e.engine.GetInstance().GetContext(ctx, &page.Total, countQuery, values...); err != nil {
+				return page, classifyError(err)
+			}
+		}
+	} else {
+		if err := e.engine.GetInstance().GetContext(ctx, &page.Total, countQuery, values...); err != nil {
+			return page, classifyError(err)
+		}
+		query := "SELECT * FROM " + tableName + whereElements + pagQuery
+		if err := e.engine.GetInstance().SelectContext(ctx, &page.Items, query, values...); err != nil {
+			return page, classifyError(err)
+		}
+	}
+
+	page.HasNext = p.Offset+int64(len(page.Items)) < page.Total
+	page.Items, err = e.withLazies(ctx, page.Items)
+	return page, err
+}
+
+// selectWithTotal scans entities together with totalColumn value
+func (e *Entity[entityType]) selectWithTotal(ctx context.Context, query string, values []interface{}) ([]entityType, int64, error) {
+	rows, err := e.engine.GetInstance().QueryxContext(ctx, query, values...)
+	if err != nil {
+		return nil, 0, classifyError(err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+	traversals := rows.Mapper.TraversalsByName(reflect.TypeOf(e.entityObj), columns)
+
+	var total int64
+	items := make([]entityType, 0)
+	for rows.Next() {
+		item := *new(entityType)
+		v := reflect.ValueOf(&item).Elem()
+
+		dest := make([]interface{}, len(columns))
+		for i, column := range columns {
+			switch {
+			case column == totalColumn:
+				dest[i] = &total
+			case len(traversals[i]) == 0:
+				dest[i] = new(interface{})
+			default:
+				dest[i] = reflectx.FieldByIndexes(v, traversals[i]).Addr().Interface()
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, classifyError(rows.Err())
+}
@@ -0,0 +1,56 @@
+package gpa
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+// Project selects rows matched by filters as FindBy does, but only columns with db tags of dtoType,
+// so large columns aren't transferred when they aren't needed.
+//
+//	titles, err := gpa.Project[DocumentTitle](gpa.From[Document](), filters, nil)
+func Project[dtoType any, entityType any](e *Entity[entityType], filters []F, p *Pagination) ([]dtoType, error) {
+	return ProjectContext[dtoType](context.Background(), e, filters, p)
+}
+
+func ProjectContext[dtoType any, entityType any](ctx context.Context, e *Entity[entityType], filters []F, p *Pagination) ([]dtoType, error) {
+	tableName, err := e.tableName()
+	if err != nil {
+		return nil, err
+	}
+
+	dtoFields, err := getReflectedData(*new(dtoType), true)
+	if err != nil {
+		return nil, err
+	}
+	if len(dtoFields) == 0 {
+		return nil, errors.Errorf("gpa projection %v has no fields with db tag", reflect.TypeOf(*new(dtoType)))
+	}
+	fields, err := getReflectedData(e.entityObj, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.validateColumns(fields, dtoFields.GetFieldsDb()); err != nil {
+		return nil, err
+	}
+
+	whereElements, values, err := e.getWhereQuery(filters, 1)
+	if err != nil {
+		return nil, err
+	}
+	pagQuery, err := e.getPagQuery(p)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := strings.Join(quoteIdents(dtoFields.GetFieldsDb()), ", ")
+	query := "SELECT " + columns + " FROM " + tableName + whereElements + pagQuery
+
+	dtos := make([]dtoType, 0)
+	if err := e.engine.GetInstance().SelectContext(ctx, &dtos, query, values...); err != nil {
+		return nil, classifyError(err)
+	}
+	return dtos, nil
+}
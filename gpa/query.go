@@ -0,0 +1,122 @@
+package gpa
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+// Executor source of database instance for raw queries, implemented by Engine and Tx
+type Executor interface {
+	GetInstance() DbProviderI
+}
+
+// GetInstance returns transaction of the Tx
+func (tx *Tx) GetInstance() DbProviderI {
+	return tx.engine.GetInstance()
+}
+
+// Query runs raw query on the default engine and maps rows to resultType,
+// which could be struct with db tags, map[string]interface{} or scalar for single column.
+//
+//	stats, err := gpa.Query[UserStats](ctx, "SELECT u.name, COUNT(*) AS roles FROM users u JOIN ...")
+func Query[resultType any](ctx context.Context, query string, args ...interface{}) ([]resultType, error) {
+	e, err := defaultEngine()
+	if err != nil {
+		return nil, err
+	}
+	return QueryWith[resultType](ctx, e, query, args...)
+}
+
+// QueryWith runs raw query with the engine or transaction
+func QueryWith[resultType any](ctx context.Context, ex Executor, query string, args ...interface{}) ([]resultType, error) {
+	isMap, err := mapScanned[resultType]()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]resultType, 0)
+	if !isMap {
+		if err := ex.GetInstance().SelectContext(ctx, &results, query, args...); err != nil {
+			return nil, classifyError(err)
+		}
+		return results, nil
+	}
+
+	rows, err := ex.GetInstance().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result := make(map[string]interface{})
+		if err := rows.MapScan(result); err != nil {
+			return nil, err
+		}
+		results = append(results, any(result).(resultType))
+	}
+	return results, classifyError(rows.Err())
+}
+
+// QueryOne runs raw query on the default engine and maps the first row to resultType, ErrNotFound when there are no rows
+func QueryOne[resultType any](ctx context.Context, query string, args ...interface{}) (resultType, error) {
+	e, err := defaultEngine()
+	if err != nil {
+		return *new(resultType), err
+	}
+	return QueryOneWith[resultType](ctx, e, query, args...)
+}
+
+// QueryOneWith runs raw query with the engine or transaction and maps the first row to resultType
+func QueryOneWith[resultType any](ctx context.Context, ex Executor, query string, args ...interface{}) (resultType, error) {
+	result := *new(resultType)
+	isMap, err := mapScanned[resultType]()
+	if err != nil {
+		return result, err
+	}
+
+	if !isMap {
+		if err := ex.GetInstance().GetContext(ctx, &result, query, args...); err != nil {
+			return result, classifyError(err)
+		}
+		return result, nil
+	}
+
+	m := make(map[string]interface{})
+	if err := ex.GetInstance().QueryRowxContext(ctx, query, args...).MapScan(m); err != nil {
+		return result, classifyError(err)
+	}
+	return any(m).(resultType), nil
+}
+
+// Exec runs raw statement on the default engine
+func Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e, err := defaultEngine()
+	if err != nil {
+		return nil, err
+	}
+	return ExecWith(ctx, e, query, args...)
+}
+
+// ExecWith runs raw statement with the engine or transaction
+func ExecWith(ctx context.Context, ex Executor, query string, args ...interface{}) (sql.Result, error) {
+	res, err := ex.GetInstance().ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	return res, nil
+}
+
+// mapScanned checks if resultType is scanned with MapScan, only map[string]interface{} maps are supported
+func mapScanned[resultType any]() (bool, error) {
+	t := reflect.TypeOf(*new(resultType))
+	if t == nil || t.Kind() != reflect.Map {
+		return false, nil
+	}
+	if t != reflect.TypeOf(map[string]interface{}{}) {
+		return false, errors.Errorf("gpa can't map rows to %v, use map[string]interface{}", t)
+	}
+	return true, nil
+}
@@ -0,0 +1,62 @@
+package gpa
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+type registerOptions struct {
+	tableName  string
+	skipCreate bool
+}
+
+type RegisterOption func(o *registerOptions)
+
+// WithTableName overrides table name resolved from GPAConfigure or entity name pluralization
+func WithTableName(tableName string) RegisterOption {
+	return func(o *registerOptions) {
+		o.tableName = tableName
+	}
+}
+
+// WithoutTableCreation returns an error instead of creating missing table
+func WithoutTableCreation() RegisterOption {
+	return func(o *registerOptions) {
+		o.skipCreate = true
+	}
+}
+
+// Register validates entity tags, resolves its table name and checks or creates the table.
+// Should be called at startup, so misconfigured entities are reported before the first request.
+// Entity is registered only once, subsequent calls and From are no-op for it,
+// options passed for already registered entity return an error.
+func Register[entityType any](e *Engine, opts ...RegisterOption) error {
+	return e.register(*new(entityType), opts...)
+}
+
+// RegisterAll registers every entity, passed as zero value, f.e. RegisterAll(e, User{}, Role{})
+func RegisterAll(e *Engine, entities ...any) error {
+	for _, entity := range entities {
+		if err := e.register(entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) register(entity any, opts ...RegisterOption) error {
+	o := registerOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	initialized := false
+	err := e.registry.initOnce(entity, func() error {
+		initialized = true
+		return e.initTable(entity, o)
+	})
+	if err == nil && !initialized && len(opts) > 0 {
+		return errors.Errorf("gpa entity %v is already registered, options can't be applied", reflect.TypeOf(entity))
+	}
+	return errors.Wrapf(err, "gpa can't register entity %v", reflect.TypeOf(entity))
+}
@@ -0,0 +1,83 @@
+package gpa
+
+import (
+	"reflect"
+	"sync"
+)
+
+// registry entities configuration, shared between Engine and its transactions.
+// Safe for concurrent use.
+type registry struct {
+	mu                 sync.RWMutex
+	entityTableNameMap map[reflect.Type]string
+	tableNameEntityMap map[string]any
+	initialized        map[reflect.Type]bool
+
+	// initMu serializes tables initialization, so table is checked and created only once
+	initMu sync.Mutex
+}
+
+func newRegistry() *registry {
+	return &registry{
+		entityTableNameMap: make(map[reflect.Type]string, 0),
+		tableNameEntityMap: make(map[string]any, 0),
+		initialized:        make(map[reflect.Type]bool, 0),
+	}
+}
+
+// setTableName maps entity to the table, previous table of the entity is unmapped
+func (r *registry) setTableName(entity any, tableName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := reflect.TypeOf(entity)
+	if prev, ok := r.entityTableNameMap[t]; ok && prev != tableName && reflect.TypeOf(r.tableNameEntityMap[prev]) == t {
+		delete(r.tableNameEntityMap, prev)
+	}
+	r.entityTableNameMap[t] = tableName
+	r.tableNameEntityMap[tableName] = entity
+}
+
+func (r *registry) getTableName(entity any) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	val, ok := r.entityTableNameMap[reflect.TypeOf(entity)]
+	return val, ok
+}
+
+func (r *registry) getEntity(tableName string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	val, ok := r.tableNameEntityMap[tableName]
+	return val, ok
+}
+
+func (r *registry) isInitialized(t reflect.Type) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.initialized[t]
+}
+
+func (r *registry) setInitialized(t reflect.Type) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.initialized[t] = true
+}
+
+// initOnce runs init for the entity type until it succeeds once, concurrent callers wait until it's finished.
+func (r *registry) initOnce(entity any, init func() error) error {
+	t := reflect.TypeOf(entity)
+	if r.isInitialized(t) {
+		return nil
+	}
+
+	r.initMu.Lock()
+	defer r.initMu.Unlock()
+	if r.isInitialized(t) {
+		return nil
+	}
+	if err := init(); err != nil {
+		return err
+	}
+	r.setInitialized(t)
+	return nil
+}
@@ -0,0 +1,131 @@
+package gpa
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type registryUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type registryRole struct {
+	ID int64 `db:"id"`
+}
+
+func TestRegistryInitOnceConcurrent(t *testing.T) {
+	r := newRegistry()
+	entities := []any{registryUser{}, registryRole{}, &registryUser{}}
+	calls := make([]int32, len(entities))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for j := range entities {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				err := r.initOnce(entities[j], func() error {
+					atomic.AddInt32(&calls[j], 1)
+					r.setTableName(entities[j], "table")
+					return nil
+				})
+				if err != nil {
+					t.Errorf("initOnce(%T) error = %v", entities[j], err)
+				}
+				r.getTableName(entities[j])
+			}(j)
+		}
+	}
+	wg.Wait()
+
+	for j, entity := range entities {
+		if calls[j] != 1 {
+			t.Errorf("init of %T called %d times, want 1", entity, calls[j])
+		}
+	}
+}
+
+func TestRegistryInitOnceRetriesFailedInit(t *testing.T) {
+	r := newRegistry()
+	initErr := errors.New("init failed")
+
+	calls := 0
+	init := func() error {
+		calls++
+		if calls == 1 {
+			return initErr
+		}
+		return nil
+	}
+
+	if err := r.initOnce(registryUser{}, init); !errors.Is(err, initErr) {
+		t.Fatalf("first initOnce error = %v, want %v", err, initErr)
+	}
+	if r.isInitialized(reflect.TypeOf(registryUser{})) {
+		t.Fatal("entity is initialized after failed init")
+	}
+	for i := 0; i < 3; i++ {
+		if err := r.initOnce(registryUser{}, init); err != nil {
+			t.Fatalf("initOnce error = %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("init called %d times, want 2", calls)
+	}
+}
+
+func TestRegisterAndFromEngineConcurrent(t *testing.T) {
+	e := NewIndependentEngine(nil, Config{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var invalid *InvalidEntityError
+			if err := Register[int](e); !errors.As(err, &invalid) {
+				t.Errorf("Register[int] error = %v, want InvalidEntityError", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			var invalid *InvalidEntityError
+			if _, err := FromEngine[int](e).tableName(); !errors.As(err, &invalid) {
+				t.Errorf("FromEngine[int] error = %v, want InvalidEntityError", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRegistrySetTableNameUnmapsPreviousTable(t *testing.T) {
+	r := newRegistry()
+	r.setTableName(registryUser{}, "registry_users")
+	r.setTableName(registryUser{}, "docs")
+
+	if name, _ := r.getTableName(registryUser{}); name != "docs" {
+		t.Errorf("table name = %q, want %q", name, "docs")
+	}
+	if _, ok := r.getEntity("registry_users"); ok {
+		t.Error("previous table is still mapped to the entity")
+	}
+	if _, ok := r.getEntity("docs"); !ok {
+		t.Error("table isn't mapped to the entity")
+	}
+}
+
+func TestRegisterOptionsForRegisteredEntity(t *testing.T) {
+	e := NewIndependentEngine(nil, Config{})
+	e.registry.setInitialized(reflect.TypeOf(registryUser{}))
+
+	if err := Register[registryUser](e); err != nil {
+		t.Errorf("Register without options error = %v", err)
+	}
+	if err := Register[registryUser](e, WithTableName("docs")); err == nil {
+		t.Error("Register with options of registered entity returned nil error")
+	}
+}
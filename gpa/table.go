@@ -3,58 +3,121 @@ package gpa
 import (
 	"fmt"
 	"github.com/gertd/go-pluralize"
+	"github.com/pkg/errors"
 	"reflect"
 	"strings"
 )
 
-func initTable(obj any, entity interface{}) {
+func (e *Engine) initTable(entity any, opts registerOptions) error {
+	if err := validateEntity(entity); err != nil {
+		return err
+	}
+
 	gpaEntity, ok := entity.(GPAEntity)
 	if ok {
-		gpaEntity.GPAConfigure(engine)
+		gpaEntity.GPAConfigure(e)
 	}
 
-	tableName, ok := engine.GetTableName(gpaEntity)
-	if !ok {
-		structName := strings.ToLower(reflect.TypeOf(obj).Name())
-		tableName = pluralize.NewClient().Plural(structName)
-
-		if !isTableExists(tableName) {
-			createTable(entity, tableName)
+	tableName := opts.tableName
+	if tableName == "" {
+		tableName, ok = e.GetTableName(entity)
+		if !ok {
+			structName := strings.ToLower(reflect.TypeOf(entity).Name())
+			tableName = pluralize.NewClient().Plural(structName)
 		}
 	}
-	if !isTableExists(tableName) {
-		createTable(entity, tableName)
+
+	exists, err := e.isTableExists(tableName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if opts.skipCreate {
+			return errors.Errorf("table %s of entity %s doesn't exist", tableName, reflect.TypeOf(entity))
+		}
+		if err := e.createTable(entity, tableName); err != nil {
+			return err
+		}
 	}
-	engine.entityTableNameMap[obj] = tableName
-	engine.tableNameEntityMap[tableName] = obj
+	e.SetTableName(entity, tableName)
+	return nil
 }
 
-func isTableExists(name string) bool {
-	rows, tableCheck := engine.GetInstance().Query("SELECT * FROM " + name + ";")
-
-	if tableCheck != nil {
-		return false
+// isTableExists checks the table without selecting from it
+func (e *Engine) isTableExists(name string) (bool, error) {
+	var exists bool
+	if err := e.GetInstance().QueryRowx("SELECT to_regclass($1) IS NOT NULL", quoteTable(name)).Scan(&exists); err != nil {
+		return false, errors.Wrapf(err, "gpa can't check table %s existence", name)
 	}
-	rows.Close()
-	return true
+	return exists, nil
 }
 
-func createTable(entity interface{}, tableName string) {
-	emd := getReflectedData(entity, true)
+func (e *Engine) createTable(entity interface{}, tableName string) error {
+	emd, err := getReflectedData(entity, true)
+	if err != nil {
+		return err
+	}
 
 	fieldsData := ""
 	for i := 0; i < len(emd); i++ {
 		pgType := getPGType(emd[i])
-		fieldsData += fmt.Sprintf("%s %s, ", emd[i].FieldDb, pgType)
+		fieldsData += fmt.Sprintf("%s %s, ", quoteIdent(emd[i].FieldDb), pgType)
 	}
 	fieldsData = fieldsData[:len(fieldsData)-2]
 
-	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", tableName, fieldsData)
-	rows, err := engine.GetInstance().Queryx(query)
-	if err != nil {
-		panic(fmt.Sprintf("gpa can't create the table with error:%s", err))
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", quoteTable(tableName), fieldsData)
+	if _, err := e.GetInstance().Exec(query); err != nil {
+		return errors.Wrapf(err, "gpa can't create the table %s", tableName)
+	}
+	return nil
+}
+
+// validateEntity checks entity struct tags before the table is resolved
+func validateEntity(entity any) error {
+	t := reflect.TypeOf(entity)
+	if t == nil || t.Kind() != reflect.Struct {
+		return &InvalidEntityError{Type: t}
+	}
+
+	dbTags := make(map[string]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag := f.Tag.Get("db"); tag != "" {
+			if field, ok := dbTags[tag]; ok {
+				return errors.Errorf("entity %s has duplicated db tag %q on fields %s and %s", t, tag, field, f.Name)
+			}
+			dbTags[tag] = f.Name
+		}
+
+		switch f.Tag.Get("fetch") {
+		case "":
+		case "lazy":
+			if f.Tag.Get("join") == "" || f.Tag.Get("mappedBy") == "" {
+				return errors.Errorf("entity %s lazy field %s should have join and mappedBy tags", t, f.Name)
+			}
+			if !isLazyType(f.Type) {
+				return errors.Errorf("entity %s lazy field %s should be *T or *[]T of struct, got %s", t, f.Name, f.Type)
+			}
+		default:
+			return errors.Errorf("entity %s field %s has unknown fetch type %q", t, f.Name, f.Tag.Get("fetch"))
+		}
+	}
+	if len(dbTags) == 0 {
+		return errors.Errorf("entity %s has no fields with db tag", t)
+	}
+	return nil
+}
+
+// isLazyType reports whether lazy field type is pointer to struct or to slice of structs
+func isLazyType(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		return false
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
 	}
-	rows.Close()
+	return t.Kind() == reflect.Struct
 }
 
 func getPGType(emd EntityMetadataInfo) string {
@@ -0,0 +1,81 @@
+package gpa
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidateEntity(t *testing.T) {
+	var nilUser *metaUser
+	tests := []struct {
+		name    string
+		entity  any
+		invalid bool
+		wantErr bool
+	}{
+		{name: "struct", entity: metaUser{}},
+		{name: "pointer", entity: &metaUser{}, invalid: true, wantErr: true},
+		{name: "slice", entity: []metaUser{}, invalid: true, wantErr: true},
+		{name: "int", entity: 1, invalid: true, wantErr: true},
+		{name: "nil interface", entity: nil, invalid: true, wantErr: true},
+		{name: "nil pointer", entity: nilUser, invalid: true, wantErr: true},
+		{name: "duplicated db tag", entity: struct {
+			A int `db:"a"`
+			B int `db:"a"`
+		}{}, wantErr: true},
+		{name: "lazy without join", entity: struct {
+			ID    int64       `db:"id"`
+			Roles *[]metaRole `fetch:"lazy"`
+		}{}, wantErr: true},
+		{name: "without db tags", entity: struct{ A int }{}, wantErr: true},
+		{name: "lazy pointer", entity: struct {
+			ID   int64     `db:"id"`
+			Role *metaRole `join:"roles" mappedBy:"id" fetch:"lazy"`
+		}{}},
+		{name: "lazy interface", entity: struct {
+			ID   int64       `db:"id"`
+			Role interface{} `db:"role_id" join:"roles" mappedBy:"id" fetch:"lazy"`
+		}{}, wantErr: true},
+		{name: "lazy struct", entity: struct {
+			ID   int64    `db:"id"`
+			Role metaRole `join:"roles" mappedBy:"id" fetch:"lazy"`
+		}{}, wantErr: true},
+		{name: "lazy slice", entity: struct {
+			ID    int64      `db:"id"`
+			Roles []metaRole `join:"roles" mappedBy:"id" fetch:"lazy"`
+		}{}, wantErr: true},
+		{name: "lazy pointer to int slice", entity: struct {
+			ID    int64    `db:"id"`
+			Roles *[]int64 `join:"roles" mappedBy:"id" fetch:"lazy"`
+		}{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEntity(tt.entity)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateEntity error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var invalid *InvalidEntityError
+			if errors.As(err, &invalid) != tt.invalid {
+				t.Errorf("validateEntity error = %v, InvalidEntityError expected %v", err, tt.invalid)
+			}
+		})
+	}
+}
+
+type lazyInterfaceEntity struct {
+	ID   int64       `db:"id"`
+	Role interface{} `db:"role_id" join:"roles" mappedBy:"id" fetch:"lazy"`
+}
+
+func TestWithsLazyInvalidField(t *testing.T) {
+	entity := newTestEntity[lazyInterfaceEntity]("lazy_interface_entities")
+	entity.engine.cfg.IsLazy = true
+	entity.engine.SetTableName(metaRole{}, "roles")
+
+	if _, err := entity.withsLazy(context.Background(), lazyInterfaceEntity{ID: 1, Role: int64(2)}); err == nil {
+		t.Error("withsLazy error = nil, want lazy field type error")
+	}
+}
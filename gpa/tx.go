@@ -0,0 +1,55 @@
+package gpa
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Tx transaction handle passed to the WithTx callback.
+// Entities bound to it with FromTx share the same database transaction.
+type Tx struct {
+	engine *Engine
+	// parent engine the transaction was started on, entity tables are initialized with it,
+	// so table creation isn't rolled back with the transaction
+	parent *Engine
+}
+
+// WithTx runs fn inside a transaction of the default engine.
+// Transaction is committed when fn returns nil and rolled back when fn returns an error or panics.
+func WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	e, err := defaultEngine()
+	if err != nil {
+		return err
+	}
+	return e.WithTx(ctx, fn)
+}
+
+// WithTx runs fn inside a new transaction. The engine itself is not modified,
+// so queries made outside of fn are not affected by the transaction.
+func (e *Engine) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	t, err := e.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "gpa can't begin transaction")
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = t.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&Tx{engine: e.withTx(t), parent: e}); err != nil {
+		_ = t.Rollback()
+		return err
+	}
+	return errors.Wrap(classifyError(t.Commit()), "gpa can't commit transaction")
+}
+
+// withTx returns a copy of engine sharing entities configuration, but bound to the transaction.
+func (e *Engine) withTx(t *sqlx.Tx) *Engine {
+	txEngine := *e
+	txEngine.t = t
+	return &txEngine
+}
@@ -0,0 +1,140 @@
+package gpa
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UpdateFields updates only listed columns of the entity row, f.e. UpdateFields(doc, "title", "views")
+func (e *Entity[entityType]) UpdateFields(entity entityType, columns ...string) error {
+	return e.UpdateFieldsContext(context.Background(), entity, columns...)
+}
+
+func (e *Entity[entityType]) UpdateFieldsContext(ctx context.Context, entity entityType, columns ...string) error {
+	fields, err := getReflectedData(entity, false)
+	if err != nil {
+		return err
+	}
+	if err := e.validateColumns(fields, columns); err != nil {
+		return err
+	}
+	return e.updateColumns(ctx, entity, columns)
+}
+
+// UpdateNonZero updates only columns of the entity with non-zero values
+func (e *Entity[entityType]) UpdateNonZero(entity entityType) error {
+	return e.UpdateNonZeroContext(context.Background(), entity)
+}
+
+func (e *Entity[entityType]) UpdateNonZeroContext(ctx context.Context, entity entityType) error {
+	fields, err := getReflectedData(entity, false)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(entity)
+	columns := make([]string, 0)
+	for _, f := range fields {
+		if !v.FieldByName(f.FieldName).IsZero() {
+			columns = append(columns, f.FieldDb)
+		}
+	}
+	return e.updateColumns(ctx, entity, columns)
+}
+
+// Patch updates columns of the row with id by values map, keys are validated against entity db tags
+func (e *Entity[entityType]) Patch(id int64, values map[string]any) error {
+	return e.PatchContext(context.Background(), id, values)
+}
+
+func (e *Entity[entityType]) PatchContext(ctx context.Context, id int64, values map[string]any) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
+	}
+
+	sets, args, err := e.setClause(values, 1)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", tableName, sets, len(args)+1)
+	res, err := e.engine.GetInstance().ExecContext(ctx, query, append(args, id)...)
+	if err != nil {
+		return classifyError(err)
+	}
+	return checkAffected(res)
+}
+
+// setClause builds SET clause for values map with placeholders starting from paramsCounter
+func (e *Entity[entityType]) setClause(values map[string]any, paramsCounter int) (string, []interface{}, error) {
+	if len(values) == 0 {
+		return "", nil, errors.New("gpa update has no columns to set")
+	}
+	fields, err := getReflectedData(e.entityObj, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	columns := make([]string, 0, len(values))
+	for c := range values {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+	if err := e.validateColumns(fields, columns); err != nil {
+		return "", nil, err
+	}
+
+	sets := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, c := range columns {
+		sets = append(sets, fmt.Sprintf("%s = $%d", quoteIdent(c), paramsCounter))
+		args = append(args, values[c])
+		paramsCounter++
+	}
+	return strings.Join(sets, ", "), args, nil
+}
+
+// updateColumns updates listed columns of the row with entity id using named parameters
+func (e *Entity[entityType]) updateColumns(ctx context.Context, entity entityType, columns []string) error {
+	tableName, err := e.tableName()
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return errors.New("gpa update has no columns to set")
+	}
+
+	values := make([]string, 0)
+	for _, c := range columns {
+		values = append(values, fmt.Sprintf("%s = :%s", quoteIdent(c), c))
+	}
+
+	queryStr := fmt.Sprintf("UPDATE %s SET %v WHERE id = :id", tableName, strings.Join(values, ","))
+
+	stmt, err := e.engine.GetInstance().PrepareNamedContext(ctx, queryStr)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, entity)
+	if err != nil {
+		return classifyError(err)
+	}
+	return checkAffected(res)
+}
+
+// validateColumns checks that every column is defined in the entity fields
+func (e *Entity[entityType]) validateColumns(fields MetaDataList, columns []string) error {
+	for _, c := range columns {
+		if fields.GetDataByDBTag(c).FieldDb == "" {
+			return &UnknownColumnError{Entity: reflect.TypeOf(e.entityObj), Column: c}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package gpa
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+// OnConflict describes ON CONFLICT clause of Upsert and Upserts
+type OnConflict struct {
+	// Columns conflict target, f.e. []string{"email"}, should be inserted columns, so id can't be used
+	Columns []string
+	// Constraint conflict target by constraint name, used when Columns are empty
+	Constraint string
+	// DoNothing skips conflicting rows instead of updating them
+	DoNothing bool
+	// Update columns set from the EXCLUDED row, all inserted columns except conflict target when empty
+	Update []string
+	// Where optional condition of DO UPDATE, f.e. "documents.views < EXCLUDED.views"
+	Where string
+}
+
+func (oc OnConflict) clause(fields []string) (string, error) {
+	target := ""
+	if len(oc.Columns) > 0 {
+		target = " (" + strings.Join(quoteIdents(oc.Columns), ",") + ")"
+	} else if oc.Constraint != "" {
+		target = " ON CONSTRAINT " + quoteIdent(oc.Constraint)
+	}
+
+	if oc.DoNothing {
+		return " ON CONFLICT" + target + " DO NOTHING", nil
+	}
+	if target == "" {
+		return "", errors.New("gpa upsert with DO UPDATE requires conflict columns or constraint")
+	}
+
+	update := oc.Update
+	if len(update) == 0 {
+		for _, f := range fields {
+			if !contains(oc.Columns, f) {
+				update = append(update, f)
+			}
+		}
+	}
+	if len(update) == 0 {
+		return "", errors.New("gpa upsert has no columns to update")
+	}
+
+	sets := make([]string, 0)
+	for _, f := range update {
+		sets = append(sets, quoteIdent(f)+" = EXCLUDED."+quoteIdent(f))
+	}
+
+	where := ""
+	if oc.Where != "" {
+		where = " WHERE " + oc.Where
+	}
+	return " ON CONFLICT" + target + " DO UPDATE SET " + strings.Join(sets, ", ") + where, nil
+}
+
+// Upsert inserts item or resolves the conflict as described by oc.
+// Item is populated back with the inserted or updated row, it's left unchanged when the row was skipped.
+func (e *Entity[entityType]) Upsert(item *entityType, oc OnConflict) error {
+	return e.UpsertContext(context.Background(), item, oc)
+}
+
+func (e *Entity[entityType]) UpsertContext(ctx context.Context, item *entityType, oc OnConflict) error {
+	if item == nil {
+		return &InvalidEntityError{Type: reflect.TypeOf(item)}
+	}
+
+	items := []entityType{*item}
+	if err := e.UpsertsContext(ctx, items, oc); err != nil {
+		return err
+	}
+	*item = items[0]
+	return nil
+}
+
+// Upserts inserts all items with one query resolving conflicts as described by oc.
+// Items are populated back with returned rows, when rows could be skipped (DoNothing or Where)
+// they are matched to items by oc.Columns values. Items can't be matched without oc.Columns,
+// f.e. with Constraint target, so they are left unchanged when some rows were skipped.
+func (e *Entity[entityType]) Upserts(items []entityType, oc OnConflict) error {
+	return e.UpsertsContext(context.Background(), items, oc)
+}
+
+func (e *Entity[entityType]) UpsertsContext(ctx context.Context, items []entityType, oc OnConflict) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	mdl, err := getReflectedData(items[0], false)
+	if err != nil {
+		return err
+	}
+	if err := e.validateColumns(mdl, oc.Columns); err != nil {
+		return err
+	}
+	if err := e.validateColumns(mdl, oc.Update); err != nil {
+		return err
+	}
+	clause, err := oc.clause(mdl.GetFieldsDb())
+	if err != nil {
+		return err
+	}
+
+	query, args, err := e.insertsQuery(items)
+	if err != nil {
+		return err
+	}
+	query += clause + " RETURNING *"
+
+	if !oc.DoNothing && oc.Where == "" {
+		return e.queryReturning(ctx, query, args, items)
+	}
+
+	returned := make([]entityType, 0)
+	if err := e.engine.GetInstance().SelectContext(ctx, &returned, query, args...); err != nil {
+		return classifyError(err)
+	}
+	if len(returned) == len(items) {
+		copy(items, returned)
+		return nil
+	}
+	populateByColumns(items, returned, mdl, oc.Columns)
+	return nil
+}
+
+// populateByColumns replaces items by returned rows with the same columns values
+func populateByColumns[entityType any](items []entityType, returned []entityType, mdl MetaDataList, columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+
+	fieldNames := make([]string, 0)
+	for _, c := range columns {
+		fieldNames = append(fieldNames, mdl.GetDataByDBTag(c).FieldName)
+	}
+
+	for _, r := range returned {
+		rv := reflect.ValueOf(r)
+		for i := range items {
+			iv := reflect.ValueOf(items[i])
+			matched := true
+			for _, name := range fieldNames {
+				if !reflect.DeepEqual(rv.FieldByName(name).Interface(), iv.FieldByName(name).Interface()) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				items[i] = r
+			}
+		}
+	}
+}
+
+func contains(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
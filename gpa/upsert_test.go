@@ -0,0 +1,98 @@
+package gpa
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOnConflictClause(t *testing.T) {
+	fields := []string{"email", "name", "views"}
+	tests := []struct {
+		name    string
+		oc      OnConflict
+		want    string
+		wantErr bool
+	}{
+		{name: "do nothing", oc: OnConflict{DoNothing: true}, want: ` ON CONFLICT DO NOTHING`},
+		{name: "do nothing on columns", oc: OnConflict{Columns: []string{"email"}, DoNothing: true}, want: ` ON CONFLICT ("email") DO NOTHING`},
+		{name: "do nothing on constraint", oc: OnConflict{Constraint: "users_email_key", DoNothing: true}, want: ` ON CONFLICT ON CONSTRAINT "users_email_key" DO NOTHING`},
+		{
+			name: "default update list",
+			oc:   OnConflict{Columns: []string{"email"}},
+			want: ` ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "views" = EXCLUDED."views"`,
+		},
+		{
+			name: "constraint target",
+			oc:   OnConflict{Constraint: `users"email`, Update: []string{"name"}},
+			want: ` ON CONFLICT ON CONSTRAINT "users""email" DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			name: "constraint target updates all columns",
+			oc:   OnConflict{Constraint: "users_email_key"},
+			want: ` ON CONFLICT ON CONSTRAINT "users_email_key" DO UPDATE SET "email" = EXCLUDED."email", "name" = EXCLUDED."name", "views" = EXCLUDED."views"`,
+		},
+		{
+			name: "where",
+			oc:   OnConflict{Columns: []string{"email", "name"}, Where: "users.views < EXCLUDED.views"},
+			want: ` ON CONFLICT ("email","name") DO UPDATE SET "views" = EXCLUDED."views" WHERE users.views < EXCLUDED.views`,
+		},
+		{name: "do update without target", oc: OnConflict{Update: []string{"name"}}, wantErr: true},
+		{name: "nothing to update", oc: OnConflict{Columns: []string{"email", "name", "views"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.oc.clause(fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clause error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("clause =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertsValidatesColumns(t *testing.T) {
+	entity := newTestEntity[bulkDocument]("documents")
+	tests := []struct {
+		name   string
+		oc     OnConflict
+		column string
+	}{
+		{name: "id conflict column", oc: OnConflict{Columns: []string{"id"}, DoNothing: true}, column: "id"},
+		{name: "unknown conflict column", oc: OnConflict{Columns: []string{"email"}}, column: "email"},
+		{name: "id update column", oc: OnConflict{Columns: []string{"title"}, Update: []string{"id"}}, column: "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := entity.Upserts([]bulkDocument{{Title: "a"}}, tt.oc)
+			var unknownColumn *UnknownColumnError
+			if !errors.As(err, &unknownColumn) || unknownColumn.Column != tt.column {
+				t.Errorf("Upserts error = %v, want UnknownColumnError of %q", err, tt.column)
+			}
+		})
+	}
+}
+
+func TestPopulateByColumns(t *testing.T) {
+	mdl, err := getReflectedData(bulkDocument{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []bulkDocument{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+	returned := []bulkDocument{{ID: 3, Title: "c", Views: 1}, {ID: 1, Title: "a", Views: 2}}
+
+	populateByColumns(items, returned, mdl, []string{"title"})
+	want := []bulkDocument{{ID: 1, Title: "a", Views: 2}, {Title: "b"}, {ID: 3, Title: "c", Views: 1}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("populateByColumns items = %+v, want %+v", items, want)
+	}
+
+	populateByColumns(items, []bulkDocument{{ID: 2, Title: "b"}}, mdl, nil)
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("populateByColumns without columns changed items to %+v", items)
+	}
+}